@@ -36,7 +36,7 @@ func (e *ActorType) String() string {
 }
 
 // MarshalYAML ensures that ActorType is serialized as a string in YAML
-func (e *ActorType) MarshalYAML() (interface{}, error) {
+func (e ActorType) MarshalYAML() (interface{}, error) {
 	return e.String(), nil
 }
 
@@ -54,7 +54,7 @@ func (e *ActorType) UnmarshalYAML(data []byte) error {
 }
 
 // MarshalJSON ensures that ActorType is serialized as a string in JSON
-func (e *ActorType) MarshalJSON() ([]byte, error) {
+func (e ActorType) MarshalJSON() ([]byte, error) {
 	return json.Marshal(e.String())
 }
 
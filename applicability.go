@@ -0,0 +1,333 @@
+package gemara
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ApplicabilityMatcher decides whether an assessment applies, given the applicability values
+// listed on its AssessmentLog.
+type ApplicabilityMatcher interface {
+	Matches(applicability []string) bool
+	String() string
+}
+
+// AnyOf returns a matcher that applies when the assessment lists at least one of values.
+// It is the matcher used by ControlEvaluation.Evaluate for its userApplicability argument.
+func AnyOf(values ...string) ApplicabilityMatcher {
+	terms := make([]ApplicabilityMatcher, len(values))
+	for i, value := range values {
+		terms[i] = applicabilityTerm{names: []string{value}, label: value}
+	}
+	return Or(terms...)
+}
+
+// AllOf returns a matcher that applies when the assessment lists every one of values.
+func AllOf(values ...string) ApplicabilityMatcher {
+	terms := make([]ApplicabilityMatcher, len(values))
+	for i, value := range values {
+		terms[i] = applicabilityTerm{names: []string{value}, label: value}
+	}
+	return And(terms...)
+}
+
+// And returns a matcher that applies when all of matchers apply.
+func And(matchers ...ApplicabilityMatcher) ApplicabilityMatcher {
+	if len(matchers) == 1 {
+		return matchers[0]
+	}
+	return applicabilityAnd(matchers)
+}
+
+// Or returns a matcher that applies when any of matchers applies. With no matchers, it never applies.
+func Or(matchers ...ApplicabilityMatcher) ApplicabilityMatcher {
+	if len(matchers) == 1 {
+		return matchers[0]
+	}
+	return applicabilityOr(matchers)
+}
+
+// Not returns a matcher that applies when matcher does not.
+func Not(matcher ApplicabilityMatcher) ApplicabilityMatcher {
+	return applicabilityNot{matcher}
+}
+
+// ParseApplicability parses a boolean applicability expression such as
+//
+//	maturity-level-2 && !internal-only
+//	("TLP:Green" || tlp_amber) and not tlp_red
+//
+// Values are combined with && (and), || (or) and ! (not), grouped with parentheses, and may be
+// double quoted when they contain spaces or operator characters. && binds tighter than ||.
+//
+// When categories are provided, typically the ApplicabilityCategories of the document's metadata,
+// every value must name one of them by id or title, and matches assessments that list the
+// category by either. Otherwise values are matched exactly.
+func ParseApplicability(expr string, categories ...Category) (ApplicabilityMatcher, error) {
+	p := &applicabilityParser{categories: categories}
+	if err := p.tokenize(expr); err != nil {
+		return nil, fmt.Errorf("invalid applicability expression %q: %w", expr, err)
+	}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("invalid applicability expression %q: empty expression", expr)
+	}
+	matcher, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid applicability expression %q: %w", expr, err)
+	}
+	return matcher, nil
+}
+
+// ParseApplicability parses expr as described by the package-level ParseApplicability,
+// resolving values against the applicability categories of the catalog.
+func (c *Catalog) ParseApplicability(expr string) (ApplicabilityMatcher, error) {
+	return ParseApplicability(expr, c.Metadata.ApplicabilityCategories...)
+}
+
+type applicabilityTerm struct {
+	// names holds the spellings that satisfy the term: the value itself, or the id and
+	// title of the category it refers to.
+	names []string
+	label string
+}
+
+func (t applicabilityTerm) Matches(applicability []string) bool {
+	for _, value := range applicability {
+		for _, name := range t.names {
+			if value == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t applicabilityTerm) String() string {
+	if t.label == "" || strings.ContainsFunc(t.label, func(r rune) bool { return !isApplicabilityValueRune(r) }) {
+		return fmt.Sprintf("%q", t.label)
+	}
+	return t.label
+}
+
+type applicabilityAnd []ApplicabilityMatcher
+
+func (a applicabilityAnd) Matches(applicability []string) bool {
+	for _, matcher := range a {
+		if !matcher.Matches(applicability) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a applicabilityAnd) String() string {
+	return joinMatchers(a, " && ")
+}
+
+type applicabilityOr []ApplicabilityMatcher
+
+func (o applicabilityOr) Matches(applicability []string) bool {
+	for _, matcher := range o {
+		if matcher.Matches(applicability) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o applicabilityOr) String() string {
+	return joinMatchers(o, " || ")
+}
+
+type applicabilityNot struct {
+	matcher ApplicabilityMatcher
+}
+
+func (n applicabilityNot) Matches(applicability []string) bool {
+	return !n.matcher.Matches(applicability)
+}
+
+func (n applicabilityNot) String() string {
+	return "!" + groupMatcher(n.matcher)
+}
+
+func joinMatchers(matchers []ApplicabilityMatcher, separator string) string {
+	parts := make([]string, len(matchers))
+	for i, matcher := range matchers {
+		parts[i] = groupMatcher(matcher)
+	}
+	return strings.Join(parts, separator)
+}
+
+// groupMatcher renders matcher, parenthesized when it combines several matchers.
+func groupMatcher(matcher ApplicabilityMatcher) string {
+	switch m := matcher.(type) {
+	case applicabilityAnd:
+		if len(m) > 1 {
+			return "(" + m.String() + ")"
+		}
+	case applicabilityOr:
+		if len(m) > 1 {
+			return "(" + m.String() + ")"
+		}
+	}
+	return matcher.String()
+}
+
+func isApplicabilityValueRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:/", r)
+}
+
+type applicabilityToken struct {
+	operator string // "&&", "||", "!", "(" or ")"; empty for values
+	value    string
+}
+
+func (t applicabilityToken) String() string {
+	if t.operator != "" {
+		return fmt.Sprintf("%q", t.operator)
+	}
+	return fmt.Sprintf("value %q", t.value)
+}
+
+type applicabilityParser struct {
+	categories []Category
+	tokens     []applicabilityToken
+	pos        int
+}
+
+func (p *applicabilityParser) tokenize(expr string) error {
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == '!':
+			p.tokens = append(p.tokens, applicabilityToken{operator: string(r)})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return fmt.Errorf("expected %c%c at offset %d", r, r, i)
+			}
+			p.tokens = append(p.tokens, applicabilityToken{operator: string([]rune{r, r})})
+			i += 2
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return fmt.Errorf("unterminated quoted value at offset %d", i)
+			}
+			p.tokens = append(p.tokens, applicabilityToken{value: string(runes[i+1 : end])})
+			i = end + 1
+		case isApplicabilityValueRune(r):
+			end := i
+			for end < len(runes) && isApplicabilityValueRune(runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			switch strings.ToLower(word) {
+			case "and":
+				p.tokens = append(p.tokens, applicabilityToken{operator: "&&"})
+			case "or":
+				p.tokens = append(p.tokens, applicabilityToken{operator: "||"})
+			case "not":
+				p.tokens = append(p.tokens, applicabilityToken{operator: "!"})
+			default:
+				p.tokens = append(p.tokens, applicabilityToken{value: word})
+			}
+			i = end
+		default:
+			return fmt.Errorf("unexpected character %q at offset %d", r, i)
+		}
+	}
+	return nil
+}
+
+func (p *applicabilityParser) accept(operator string) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].operator == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *applicabilityParser) parseOr() (ApplicabilityMatcher, error) {
+	var matchers []ApplicabilityMatcher
+	for {
+		matcher, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+		if !p.accept("||") {
+			return Or(matchers...), nil
+		}
+	}
+}
+
+func (p *applicabilityParser) parseAnd() (ApplicabilityMatcher, error) {
+	var matchers []ApplicabilityMatcher
+	for {
+		matcher, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, matcher)
+		if !p.accept("&&") {
+			return And(matchers...), nil
+		}
+	}
+}
+
+func (p *applicabilityParser) parseUnary() (ApplicabilityMatcher, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if p.accept("!") {
+		matcher, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(matcher), nil
+	}
+	if p.accept("(") {
+		matcher, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return matcher, nil
+	}
+	token := p.tokens[p.pos]
+	if token.operator != "" {
+		return nil, fmt.Errorf("unexpected %s", token)
+	}
+	p.pos++
+	return p.term(token.value)
+}
+
+// term resolves value against the categories, if any.
+func (p *applicabilityParser) term(value string) (ApplicabilityMatcher, error) {
+	if len(p.categories) == 0 {
+		return applicabilityTerm{names: []string{value}, label: value}, nil
+	}
+	for _, category := range p.categories {
+		if value == category.Id || value == category.Title {
+			names := []string{category.Id}
+			if category.Title != "" {
+				names = append(names, category.Title)
+			}
+			return applicabilityTerm{names: names, label: value}, nil
+		}
+	}
+	return nil, fmt.Errorf("%q does not match any applicability category", value)
+}
@@ -0,0 +1,105 @@
+package gemara
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseApplicability(t *testing.T) {
+	tests := []struct {
+		name      string
+		expr      string
+		matches   [][]string
+		unmatched [][]string
+		rendered  string
+	}{
+		{
+			name:      "and not",
+			expr:      `maturity-level-2 && !internal-only`,
+			matches:   [][]string{{"maturity-level-2"}, {"maturity-level-2", "public"}},
+			unmatched: [][]string{{"maturity-level-2", "internal-only"}, {"maturity-level-1"}},
+			rendered:  "maturity-level-2 && !internal-only",
+		},
+		{
+			name:      "or binds looser than and",
+			expr:      `a || b && c`,
+			matches:   [][]string{{"a"}, {"b", "c"}},
+			unmatched: [][]string{{"b"}, {"c"}},
+			rendered:  "a || (b && c)",
+		},
+		{
+			name:      "keywords, grouping and quoting",
+			expr:      `("Maturity Level 2" or tlp_amber) AND NOT tlp_red`,
+			matches:   [][]string{{"Maturity Level 2"}, {"tlp_amber", "tlp_green"}},
+			unmatched: [][]string{{"tlp_amber", "tlp_red"}, {"tlp_green"}},
+			rendered:  `("Maturity Level 2" || tlp_amber) && !tlp_red`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := ParseApplicability(tt.expr)
+			require.NoError(t, err)
+			for _, applicability := range tt.matches {
+				assert.True(t, matcher.Matches(applicability), "%v", applicability)
+			}
+			for _, applicability := range tt.unmatched {
+				assert.False(t, matcher.Matches(applicability), "%v", applicability)
+			}
+			assert.Equal(t, tt.rendered, matcher.String())
+		})
+	}
+}
+
+func TestParseApplicability_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "a &&", "a & b", "(a || b", "a b", `"a`, "!", "a $ b"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseApplicability(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseApplicability_Categories(t *testing.T) {
+	catalog := &Catalog{}
+	require.NoError(t, catalog.LoadFile("file://test-data/good-ccc.yaml"))
+
+	matcher, err := catalog.ParseApplicability(`"TLP:Green" && !tlp_red`)
+	require.NoError(t, err)
+	assert.True(t, matcher.Matches([]string{"tlp_green"}), "category id should match its title")
+	assert.True(t, matcher.Matches([]string{"TLP:Green"}))
+	assert.False(t, matcher.Matches([]string{"tlp_green", "TLP:Red"}), "category title should match its id")
+
+	_, err = catalog.ParseApplicability("tlp_greem")
+	assert.ErrorContains(t, err, `"tlp_greem" does not match any applicability category`)
+}
+
+func TestEvaluateMatching(t *testing.T) {
+	internal := passingAssessmentPtr()
+	internal.Applicability = []string{"test-applicability", "internal-only"}
+	public := needsReviewAssessmentPtr()
+	control := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{internal, public}}
+
+	matcher, err := ParseApplicability("test-applicability && !internal-only")
+	require.NoError(t, err)
+	control.EvaluateMatching(context.Background(), nil, matcher)
+
+	assert.Equal(t, NotApplicable, internal.Result)
+	assert.Zero(t, internal.StepsExecuted)
+	assert.Equal(t, "applicability [test-applicability internal-only] does not satisfy test-applicability && !internal-only", internal.Message)
+	assert.Equal(t, NeedsReview, public.Result)
+	assert.Equal(t, NeedsReview, control.Result)
+}
+
+func TestEvaluate_NoneApplicable(t *testing.T) {
+	control := &ControlEvaluation{AssessmentLogs: []*AssessmentLog{passingAssessmentPtr(), failingAssessmentPtr()}}
+	control.Evaluate(nil, []string{"other-applicability"})
+
+	assert.Equal(t, NotApplicable, control.Result)
+	for _, assessment := range control.AssessmentLogs {
+		assert.Equal(t, NotApplicable, assessment.Result)
+		assert.Zero(t, assessment.StepsExecuted)
+	}
+}
@@ -1,25 +1,46 @@
 package gemara
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"runtime/debug"
 	"time"
 )
 
 // AssessmentStep is a function type that inspects the provided targetData and returns a Result with a message and confidence level.
 // The message may be an error string or other descriptive text.
+// It adapts the function to the Step interface, so it does not observe cancellation; RunContext
+// stops waiting for such a step once its context is done, but cannot interrupt it.
+// Steps that need the bound parameters of the assessment can be written as a ParameterizedStep.
 type AssessmentStep func(payload interface{}) (Result, string, ConfidenceLevel)
 
 func (as AssessmentStep) String() string {
+	return functionName(as)
+}
+
+// Name returns the fully qualified name of the function, as String does.
+func (as AssessmentStep) Name() string {
+	return as.String()
+}
+
+// Run calls as with the payload of input.
+func (as AssessmentStep) Run(_ context.Context, input StepInput) StepResult {
+	result, message, confidence := as(input.Payload)
+	return StepResult{Result: result, Message: message, ConfidenceLevel: confidence}
+}
+
+// functionName returns the fully qualified name of the function fn.
+func functionName(fn interface{}) string {
 	// Get the function pointer correctly
-	fn := runtime.FuncForPC(reflect.ValueOf(as).Pointer())
-	if fn == nil {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
 		return "<unknown function>"
 	}
-	return fn.Name()
+	return f.Name()
 }
 
 func (as AssessmentStep) MarshalJSON() ([]byte, error) {
@@ -32,6 +53,17 @@ func (as AssessmentStep) MarshalYAML() (interface{}, error) {
 
 // NewAssessment creates a new AssessmentLog object and returns a pointer to it.
 func NewAssessment(requirementId string, description string, applicability []string, steps []AssessmentStep) (*AssessmentLog, error) {
+	var list StepList
+	if steps != nil {
+		list = make(StepList, len(steps))
+		for i, step := range steps {
+			list[i] = step
+		}
+	}
+	return newAssessment(requirementId, description, applicability, list)
+}
+
+func newAssessment(requirementId string, description string, applicability []string, steps StepList) (*AssessmentLog, error) {
 	a := &AssessmentLog{
 		Requirement: SingleMapping{
 			EntryId: requirementId,
@@ -45,31 +77,162 @@ func NewAssessment(requirementId string, description string, applicability []str
 	return a, err
 }
 
-// AddStep queues a new step in the AssessmentLog
+// AddStep queues a new step in the AssessmentLog.
+// Other Step implementations can be appended to Steps directly.
 func (a *AssessmentLog) AddStep(step AssessmentStep) {
 	a.Steps = append(a.Steps, step)
 }
 
-func (a *AssessmentLog) runStep(targetData interface{}, step AssessmentStep) Result {
+// RunOption defines an option to configure how assessments and evaluations are run.
+type RunOption func(o *runOptions)
+
+type runOptions struct {
+	stepTimeout       time.Duration
+	assessmentTimeout time.Duration
+	concurrency       int
+	collector         *Actor
+}
+
+// WithStepTimeout is a RunOption that limits the duration of each step.
+// A step that exceeds it reports Unknown and the assessment continues with the next step.
+func WithStepTimeout(timeout time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.stepTimeout = timeout
+	}
+}
+
+// WithAssessmentTimeout is a RunOption that limits the duration of each assessment.
+// When it expires, the running step reports Unknown and the remaining steps are not run.
+func WithAssessmentTimeout(timeout time.Duration) RunOption {
+	return func(o *runOptions) {
+		o.assessmentTimeout = timeout
+	}
+}
+
+func newRunOptions(opts []RunOption) *runOptions {
+	options := &runOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// runStepContext runs step and records its outcome. It also reports whether the step panicked.
+func (a *AssessmentLog) runStepContext(ctx context.Context, targetData interface{}, step Step, options *runOptions) (Result, bool) {
 	a.StepsExecuted++
-	result, message, confidence := step(targetData)
-	a.Result = UpdateAggregateResult(a.Result, result)
+	name := stepName(step)
+	start := time.Now()
+	outcome := callStep(ctx, StepInput{Payload: targetData, Parameters: a.Parameters}, step, options.stepTimeout)
+	end := time.Now()
+	a.recordEvidence(name, options.collector, outcome.Evidence)
+	a.StepRecords = append(a.StepRecords, StepRecord{
+		Name:            name,
+		Result:          outcome.Result,
+		Message:         outcome.Message,
+		ConfidenceLevel: outcome.ConfidenceLevel,
+		Start:           Datetime(start.Format(time.RFC3339)),
+		End:             Datetime(end.Format(time.RFC3339)),
+		Duration:        end.Sub(start).String(),
+		Stack:           outcome.stack,
+	})
+	a.Result = UpdateAggregateResult(a.Result, outcome.Result)
 
 	// Always update message to show what steps have been run and their context.
-	a.Message = message
+	a.Message = outcome.Message
 
 	// Always use the confidence level from the last step executed.
 	// This gives step implementers full control over how confidence builds
 	// as steps are executed, allowing them to adapt confidence based on
 	// the cumulative context of all previous steps.
-	a.ConfidenceLevel = confidence
+	a.ConfidenceLevel = outcome.ConfidenceLevel
+
+	return outcome.Result, outcome.stack != ""
+}
+
+// stepOutcome is what a step reported, or what was reported on its behalf when it did not return.
+type stepOutcome struct {
+	StepResult
+	// stack is set when the step panicked.
+	stack string
+}
+
+// callStep runs step with ctx, limited to timeout when it is positive. If the context is done
+// before the step returns, the step is abandoned and reported as Unknown.
+func callStep(ctx context.Context, input StepInput, step Step, timeout time.Duration) stepOutcome {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("step timeout of %s exceeded", timeout))
+		defer cancel()
+	}
+	if ctx.Done() == nil {
+		return recoverStep(ctx, input, step)
+	}
+
+	// Buffered so an abandoned step can still deliver its outcome and exit.
+	done := make(chan stepOutcome, 1)
+	go func() {
+		done <- recoverStep(ctx, input, step)
+	}()
+	select {
+	case outcome := <-done:
+		// A step that observes cancellation returns once ctx is done; report why it stopped.
+		if ctx.Err() == nil || outcome.stack != "" {
+			return outcome
+		}
+	case <-ctx.Done():
+	}
+	return stepOutcome{StepResult: StepResult{
+		Result:          Unknown,
+		Message:         stepInterruptedMessage(ctx, step),
+		ConfidenceLevel: Undetermined,
+	}}
+}
+
+// recoverStep runs step, turning a panic into an Unknown outcome that carries the stack trace,
+// so a broken step cannot bring down the rest of the evaluation.
+func recoverStep(ctx context.Context, input StepInput, step Step) (outcome stepOutcome) {
+	defer func() {
+		if r := recover(); r != nil {
+			outcome = stepOutcome{
+				StepResult: StepResult{
+					Result:          Unknown,
+					Message:         fmt.Sprintf("step %s panicked: %v", stepName(step), r),
+					ConfidenceLevel: Undetermined,
+				},
+				stack: string(debug.Stack()),
+			}
+		}
+	}()
+	return stepOutcome{StepResult: step.Run(ctx, input)}
+}
 
-	return result
+func stepInterruptedMessage(ctx context.Context, step Step) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Sprintf("step %s timed out: %v", stepName(step), context.Cause(ctx))
+	}
+	return fmt.Sprintf("step %s was cancelled: %v", stepName(step), context.Cause(ctx))
 }
 
 // Run will execute all steps, halting if any step does not return Passed.
 func (a *AssessmentLog) Run(targetData interface{}) Result {
+	return a.RunContext(context.Background(), targetData)
+}
+
+// RunContext executes all steps like Run, passing ctx to each Step.
+// Each Step receives targetData and the bound Parameters of the assessment in its StepInput.
+// The outcome of each step that runs is traced in StepRecords, and the evidence steps return is
+// kept in Evidence, replacing the records and evidence of any previous run. The evidence of a
+// step that is abandoned because it timed out or was cancelled is discarded.
+// If ctx is cancelled or a timeout set with opts expires, the running step is reported as
+// Unknown with an Undetermined confidence level and a message explaining why.
+// Cancelling ctx or exceeding the assessment timeout also halts the remaining steps.
+// A step that panics is reported the same way, with its stack trace in the step record,
+// and halts the remaining steps of the assessment without affecting other assessments.
+func (a *AssessmentLog) RunContext(ctx context.Context, targetData interface{}, opts ...RunOption) Result {
+	options := newRunOptions(opts)
 	a.Result = NotRun
+	a.StepRecords = nil
+	a.Evidence = nil
 	if a.Result != NotRun {
 		return a.Result
 	}
@@ -81,10 +244,19 @@ func (a *AssessmentLog) Run(targetData interface{}) Result {
 		a.ConfidenceLevel = Undetermined
 		return a.Result
 	}
+	if options.assessmentTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, options.assessmentTimeout, fmt.Errorf("assessment timeout of %s exceeded", options.assessmentTimeout))
+		defer cancel()
+	}
 	for _, step := range a.Steps {
-		if a.runStep(targetData, step) == Failed {
+		result, panicked := a.runStepContext(ctx, targetData, step, options)
+		if result == Failed {
 			return Failed
 		}
+		if panicked || ctx.Err() != nil {
+			break
+		}
 	}
 	a.End = Datetime(time.Now().Format(time.RFC3339))
 	return a.Result
@@ -1,7 +1,9 @@
 package gemara
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -71,7 +73,7 @@ func TestAddStep(t *testing.T) {
 	}
 }
 
-// TestRunStep ensures that runStep runs the step and updates the AssessmentLog
+// TestRunStep ensures that runStepContext runs the step and updates the AssessmentLog
 func TestRunStep(t *testing.T) {
 	stepsTestData := []struct {
 		testName        string
@@ -107,7 +109,7 @@ func TestRunStep(t *testing.T) {
 	for _, test := range stepsTestData {
 		t.Run(test.testName, func(t *testing.T) {
 			anyOldAssessment := AssessmentLog{}
-			result := anyOldAssessment.runStep(nil, test.step)
+			result, _ := anyOldAssessment.runStepContext(context.Background(), nil, test.step, &runOptions{})
 			if result != test.result {
 				t.Errorf("expected %s, got %s", test.result, result)
 			}
@@ -371,3 +373,175 @@ func TestConfidenceLevelFromSteps(t *testing.T) {
 		})
 	}
 }
+
+func TestStepList_Unmarshal(t *testing.T) {
+	name := "github.com/example/plugin/steps.checkBranchProtection"
+
+	t.Run("YAML", func(t *testing.T) {
+		var steps StepList
+		require.NoError(t, steps.UnmarshalYAML([]byte("- "+name)))
+		require.Len(t, steps, 1)
+		assert.Equal(t, name, steps[0].Name())
+
+		result := steps[0].Run(context.Background(), StepInput{})
+		assert.Equal(t, Unknown, result.Result)
+		assert.Contains(t, result.Message, name)
+		assert.Equal(t, Undetermined, result.ConfidenceLevel)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var steps StepList
+		require.NoError(t, steps.UnmarshalJSON([]byte(`["`+name+`"]`)))
+		data, err := steps.MarshalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, `["`+name+`"]`, string(data))
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		var steps StepList
+		assert.Error(t, steps.UnmarshalJSON([]byte(`not json`)))
+		assert.Nil(t, steps)
+	})
+}
+
+func waitForCancellation(ctx context.Context, _ StepInput) StepResult {
+	<-ctx.Done()
+	return StepResult{Result: Failed, Message: "should have been abandoned", ConfidenceLevel: High}
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("Step timeout", func(t *testing.T) {
+		assessment, err := newAssessment("test-id", "test description", []string{"test"}, StepList{
+			StepFunc(waitForCancellation),
+			passingAssessmentStep,
+		})
+		require.NoError(t, err)
+
+		result := assessment.RunContext(context.Background(), nil, WithStepTimeout(10*time.Millisecond))
+		assert.Equal(t, Unknown, result)
+		assert.Equal(t, int64(2), assessment.StepsExecuted)
+		assert.Equal(t, High, assessment.ConfidenceLevel)
+	})
+
+	t.Run("Assessment timeout with a legacy step", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		blocking := func(interface{}) (Result, string, ConfidenceLevel) {
+			<-release
+			return Passed, "released", High
+		}
+		assessment, err := NewAssessment("test-id", "test description", []string{"test"}, []AssessmentStep{blocking, passingAssessmentStep})
+		require.NoError(t, err)
+
+		result := assessment.RunContext(context.Background(), nil, WithAssessmentTimeout(10*time.Millisecond))
+		assert.Equal(t, Unknown, result)
+		assert.Equal(t, Undetermined, assessment.ConfidenceLevel)
+		assert.Equal(t, int64(1), assessment.StepsExecuted)
+		assert.Contains(t, assessment.Message, "timed out: assessment timeout of 10ms exceeded")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assessment, err := newAssessment("test-id", "test description", []string{"test"}, StepList{StepFunc(waitForCancellation)})
+		require.NoError(t, err)
+
+		assert.Equal(t, Unknown, assessment.RunContext(ctx, nil))
+		assert.Contains(t, assessment.Message, "was cancelled: context canceled")
+	})
+
+	t.Run("Run passes a background context", func(t *testing.T) {
+		step := StepFunc(func(ctx context.Context, input StepInput) StepResult {
+			if ctx.Done() != nil || input.Payload != "target" {
+				return StepResult{Result: Failed, Message: "unexpected context or payload", ConfidenceLevel: Low}
+			}
+			return StepResult{Result: Passed, Message: "ok", ConfidenceLevel: High}
+		})
+		assessment, err := newAssessment("test-id", "test description", []string{"test"}, StepList{step})
+		require.NoError(t, err)
+		assert.Equal(t, Passed, assessment.Run("target"))
+	})
+}
+
+func TestStepFunc_Name(t *testing.T) {
+	steps := StepList{StepFunc(waitForCancellation), NamedStep("example.wait", StepFunc(waitForCancellation))}
+	assert.Equal(t, []string{"github.com/ossf/gemara.waitForCancellation", "example.wait"}, steps.Names())
+
+	data, err := steps.MarshalYAML()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github.com/ossf/gemara.waitForCancellation", "example.wait"}, data)
+}
+
+func TestRun_StepRecords(t *testing.T) {
+	assessment := failingAssessment()
+	assessment.Run(nil)
+
+	require.Len(t, assessment.StepRecords, 1, "steps after a failure are not recorded")
+	record := assessment.StepRecords[0]
+	assert.Equal(t, assessment.Steps[0].Name(), record.Name)
+	assert.Equal(t, Failed, record.Result)
+	assert.Equal(t, Low, record.ConfidenceLevel)
+	assert.NotEmpty(t, record.Start)
+	_, err := time.ParseDuration(record.Duration)
+	assert.NoError(t, err)
+
+	needsReview := needsReviewAssessment()
+	needsReview.Run(nil)
+	require.Len(t, needsReview.StepRecords, 3)
+	needsReview.Run(nil)
+	assert.Len(t, needsReview.StepRecords, 3, "records are replaced on each run")
+
+	data, err := Marshal(&EvaluationLog{Evaluations: []*ControlEvaluation{{AssessmentLogs: []*AssessmentLog{&needsReview}}}}, FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "step-records:\n")
+	assert.Contains(t, string(data), "steps:\n")
+}
+
+func TestStepRecords_Load(t *testing.T) {
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+	records := log.Evaluations[1].AssessmentLogs[0].StepRecords
+	require.Len(t, records, 2)
+	assert.Equal(t, log.Evaluations[1].AssessmentLogs[0].Steps[1].Name(), records[1].Name)
+	assert.Equal(t, "734ms", records[1].Duration)
+	assert.Empty(t, log.Evaluations[0].AssessmentLogs[0].StepRecords)
+}
+
+func panickingStep(payload interface{}) (Result, string, ConfidenceLevel) {
+	repository := payload.(map[string]string)
+	return Passed, repository["name"], High
+}
+
+func TestRun_Panic(t *testing.T) {
+	// Steps run inline without a deadline and in a goroutine with one; both must recover.
+	for _, opts := range [][]RunOption{nil, {WithStepTimeout(time.Second)}} {
+		assessment, err := NewAssessment("test-id", "test description", []string{"test"}, []AssessmentStep{
+			passingAssessmentStep,
+			panickingStep,
+			passingAssessmentStep,
+		})
+		require.NoError(t, err)
+
+		result := assessment.RunContext(context.Background(), nil, opts...)
+		assert.Equal(t, Unknown, result)
+		assert.Equal(t, Undetermined, assessment.ConfidenceLevel)
+		assert.Equal(t, int64(2), assessment.StepsExecuted, "the remaining steps are halted")
+		assert.Contains(t, assessment.Message, "step github.com/ossf/gemara.panickingStep panicked: interface conversion")
+
+		require.Len(t, assessment.StepRecords, 2)
+		assert.Empty(t, assessment.StepRecords[0].Stack)
+		assert.Contains(t, assessment.StepRecords[1].Stack, "gemara.panickingStep")
+	}
+}
+
+func TestEvaluate_PanicContinues(t *testing.T) {
+	log := &EvaluationLog{}
+	for _, step := range []AssessmentStep{panickingStep, passingAssessmentStep} {
+		evaluation := &ControlEvaluation{}
+		evaluation.AddAssessment("requirement", "description", testingApplicability, []AssessmentStep{step})
+		log.Evaluations = append(log.Evaluations, evaluation)
+	}
+	log.Evaluate(nil, testingApplicability)
+	assert.Equal(t, Unknown, log.Evaluations[0].Result)
+	assert.Equal(t, Passed, log.Evaluations[1].Result)
+}
@@ -0,0 +1,260 @@
+package gemara
+
+import (
+	"fmt"
+)
+
+// CatalogFetcher retrieves the catalog described by a mapping reference.
+type CatalogFetcher func(reference MappingReference) (*Catalog, error)
+
+// FetchCatalogFromURL is a CatalogFetcher that loads the catalog from the reference's url with DefaultFetcher,
+// as NewCatalogFetcher(DefaultFetcher) does.
+func FetchCatalogFromURL(reference MappingReference) (*Catalog, error) {
+	return NewCatalogFetcher(DefaultFetcher)(reference)
+}
+
+// ResolvedCatalog is a catalog whose imports have been materialized, along with the
+// provenance of every imported entry.
+type ResolvedCatalog struct {
+	// Catalog contains local and imported entries. Its Imported* fields are empty.
+	Catalog Catalog
+	// ControlSources maps the id of each imported control to the catalog it was imported from.
+	ControlSources map[string]SingleMapping
+	// ThreatSources maps the id of each imported threat to the catalog it was imported from.
+	ThreatSources map[string]SingleMapping
+	// CapabilitySources maps the id of each imported capability to the catalog it was imported from.
+	CapabilitySources map[string]SingleMapping
+}
+
+// IsImported reports whether the control, threat or capability with the given id was imported.
+func (r *ResolvedCatalog) IsImported(id string) bool {
+	if _, ok := r.ControlSources[id]; ok {
+		return true
+	}
+	if _, ok := r.ThreatSources[id]; ok {
+		return true
+	}
+	_, ok := r.CapabilitySources[id]
+	return ok
+}
+
+// Resolve follows the ImportedControls, ImportedThreats and ImportedCapabilities of the catalog
+// and returns a flattened copy that contains the imported entries, along with the mapping
+// references of the catalogs they were imported from, deduplicated by id.
+// Each MultiMapping.ReferenceId must match a MappingReference in the catalog metadata; fetch is
+// called once per referenced document. Imported catalogs are resolved recursively, and provenance
+// always points at the catalog where an entry is defined.
+func (c *Catalog) Resolve(fetch CatalogFetcher) (*ResolvedCatalog, error) {
+	r := &catalogResolver{
+		fetch:   fetch,
+		fetched: make(map[string]*ResolvedCatalog),
+	}
+	return r.resolve(c, nil)
+}
+
+type catalogResolver struct {
+	fetch   CatalogFetcher
+	fetched map[string]*ResolvedCatalog
+}
+
+func (r *catalogResolver) resolve(c *Catalog, chain []string) (*ResolvedCatalog, error) {
+	resolved := &ResolvedCatalog{
+		Catalog:           *c,
+		ControlSources:    make(map[string]SingleMapping),
+		ThreatSources:     make(map[string]SingleMapping),
+		CapabilitySources: make(map[string]SingleMapping),
+	}
+	resolved.Catalog.Metadata.MappingReferences = append([]MappingReference(nil), c.Metadata.MappingReferences...)
+	resolved.Catalog.Families = append([]Family{}, c.Families...)
+	resolved.Catalog.Controls = append([]Control{}, c.Controls...)
+	resolved.Catalog.Threats = append([]Threat{}, c.Threats...)
+	resolved.Catalog.Capabilities = append([]Capability{}, c.Capabilities...)
+	resolved.Catalog.ImportedControls = nil
+	resolved.Catalog.ImportedThreats = nil
+	resolved.Catalog.ImportedCapabilities = nil
+
+	familyIds := make(map[string]bool)
+	for _, family := range resolved.Catalog.Families {
+		familyIds[family.Id] = true
+	}
+	controlIds := make(map[string]bool)
+	for _, control := range resolved.Catalog.Controls {
+		controlIds[control.Id] = true
+	}
+	threatIds := make(map[string]bool)
+	for _, threat := range resolved.Catalog.Threats {
+		threatIds[threat.Id] = true
+	}
+	capabilityIds := make(map[string]bool)
+	for _, capability := range resolved.Catalog.Capabilities {
+		capabilityIds[capability.Id] = true
+	}
+
+	for _, mapping := range c.ImportedControls {
+		source, err := r.source(c, mapping.ReferenceId, chain)
+		if err != nil {
+			return nil, err
+		}
+		resolved.addMappingReferences(source)
+		for _, entry := range mapping.Entries {
+			control, ok := findControl(&source.Catalog, entry.ReferenceId)
+			if !ok {
+				return nil, fmt.Errorf("control %q not found in imported catalog %q", entry.ReferenceId, mapping.ReferenceId)
+			}
+			if controlIds[control.Id] {
+				return nil, fmt.Errorf("imported control %q conflicts with an existing control", control.Id)
+			}
+			controlIds[control.Id] = true
+			resolved.Catalog.Controls = append(resolved.Catalog.Controls, control)
+			resolved.ControlSources[control.Id] = provenance(mapping, entry, source.ControlSources)
+
+			// Imported controls keep their family, so the family must travel with them.
+			if !familyIds[control.Family] {
+				if family, ok := findFamily(&source.Catalog, control.Family); ok {
+					familyIds[family.Id] = true
+					resolved.Catalog.Families = append(resolved.Catalog.Families, family)
+				}
+			}
+		}
+	}
+
+	for _, mapping := range c.ImportedThreats {
+		source, err := r.source(c, mapping.ReferenceId, chain)
+		if err != nil {
+			return nil, err
+		}
+		resolved.addMappingReferences(source)
+		for _, entry := range mapping.Entries {
+			threat, ok := findThreat(&source.Catalog, entry.ReferenceId)
+			if !ok {
+				return nil, fmt.Errorf("threat %q not found in imported catalog %q", entry.ReferenceId, mapping.ReferenceId)
+			}
+			if threatIds[threat.Id] {
+				return nil, fmt.Errorf("imported threat %q conflicts with an existing threat", threat.Id)
+			}
+			threatIds[threat.Id] = true
+			resolved.Catalog.Threats = append(resolved.Catalog.Threats, threat)
+			resolved.ThreatSources[threat.Id] = provenance(mapping, entry, source.ThreatSources)
+		}
+	}
+
+	for _, mapping := range c.ImportedCapabilities {
+		source, err := r.source(c, mapping.ReferenceId, chain)
+		if err != nil {
+			return nil, err
+		}
+		resolved.addMappingReferences(source)
+		for _, entry := range mapping.Entries {
+			capability, ok := findCapability(&source.Catalog, entry.ReferenceId)
+			if !ok {
+				return nil, fmt.Errorf("capability %q not found in imported catalog %q", entry.ReferenceId, mapping.ReferenceId)
+			}
+			if capabilityIds[capability.Id] {
+				return nil, fmt.Errorf("imported capability %q conflicts with an existing capability", capability.Id)
+			}
+			capabilityIds[capability.Id] = true
+			resolved.Catalog.Capabilities = append(resolved.Catalog.Capabilities, capability)
+			resolved.CapabilitySources[capability.Id] = provenance(mapping, entry, source.CapabilitySources)
+		}
+	}
+
+	return resolved, nil
+}
+
+// addMappingReferences adds the mapping references of source that are not yet defined.
+// Imported entries keep their mappings, which refer to the references of their source.
+func (r *ResolvedCatalog) addMappingReferences(source *ResolvedCatalog) {
+	for _, reference := range source.Catalog.Metadata.MappingReferences {
+		addMappingReference(&r.Catalog.Metadata, reference)
+	}
+}
+
+// source fetches and resolves the catalog identified by referenceId in the metadata of c.
+func (r *catalogResolver) source(c *Catalog, referenceId string, chain []string) (*ResolvedCatalog, error) {
+	reference, ok := findMappingReference(c.Metadata, referenceId)
+	if !ok {
+		return nil, fmt.Errorf("no mapping reference found for imported reference-id %q", referenceId)
+	}
+	key := reference.Id + "@" + reference.Version + "@" + reference.Url
+	for _, visited := range chain {
+		if visited == key {
+			return nil, fmt.Errorf("circular catalog import detected for reference-id %q", referenceId)
+		}
+	}
+	if resolved, ok := r.fetched[key]; ok {
+		return resolved, nil
+	}
+
+	imported, err := r.fetch(reference)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imported catalog %q: %w", referenceId, err)
+	}
+	resolved, err := r.resolve(imported, append(chain, key))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving imported catalog %q: %w", referenceId, err)
+	}
+	r.fetched[key] = resolved
+	return resolved, nil
+}
+
+// provenance returns the source of an imported entry. Entries that were themselves imported
+// into the source catalog keep their original source.
+func provenance(mapping MultiMapping, entry MappingEntry, upstream map[string]SingleMapping) SingleMapping {
+	if source, ok := upstream[entry.ReferenceId]; ok {
+		return source
+	}
+	remarks := entry.Remarks
+	if remarks == "" {
+		remarks = mapping.Remarks
+	}
+	return SingleMapping{
+		ReferenceId: mapping.ReferenceId,
+		EntryId:     entry.ReferenceId,
+		Remarks:     remarks,
+	}
+}
+
+func findMappingReference(metadata Metadata, id string) (MappingReference, bool) {
+	for _, reference := range metadata.MappingReferences {
+		if reference.Id == id {
+			return reference, true
+		}
+	}
+	return MappingReference{}, false
+}
+
+func findFamily(c *Catalog, id string) (Family, bool) {
+	for _, family := range c.Families {
+		if family.Id == id {
+			return family, true
+		}
+	}
+	return Family{}, false
+}
+
+func findControl(c *Catalog, id string) (Control, bool) {
+	for _, control := range c.Controls {
+		if control.Id == id {
+			return control, true
+		}
+	}
+	return Control{}, false
+}
+
+func findThreat(c *Catalog, id string) (Threat, bool) {
+	for _, threat := range c.Threats {
+		if threat.Id == id {
+			return threat, true
+		}
+	}
+	return Threat{}, false
+}
+
+func findCapability(c *Catalog, id string) (Capability, bool) {
+	for _, capability := range c.Capabilities {
+		if capability.Id == id {
+			return capability, true
+		}
+	}
+	return Capability{}, false
+}
@@ -0,0 +1,201 @@
+package gemara
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Resolve_FromURL(t *testing.T) {
+	c := &Catalog{}
+	require.NoError(t, c.LoadFile("file://test-data/good-imported-catalog.yaml"))
+
+	resolved, err := c.Resolve(NewCatalogFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	assert.Empty(t, resolved.Catalog.ImportedControls)
+	require.Len(t, resolved.Catalog.Controls, 3)
+	assert.Equal(t, "ORG-01", resolved.Catalog.Controls[0].Id)
+	assert.Equal(t, "OSPS-AC-01", resolved.Catalog.Controls[1].Id)
+	assert.Equal(t, "OSPS-AC-03", resolved.Catalog.Controls[2].Id)
+	assert.Len(t, resolved.Catalog.Controls[2].AssessmentRequirements, 2)
+
+	require.Len(t, resolved.Catalog.Families, 2, "the family of imported controls should be imported once")
+	assert.Equal(t, "access-control", resolved.Catalog.Families[1].Id)
+
+	assert.False(t, resolved.IsImported("ORG-01"))
+	assert.True(t, resolved.IsImported("OSPS-AC-01"))
+	assert.Equal(t, SingleMapping{
+		ReferenceId: "OSPS-B",
+		EntryId:     "OSPS-AC-03",
+		Remarks:     "Primary branch protection",
+	}, resolved.ControlSources["OSPS-AC-03"])
+
+	assert.Len(t, c.Controls, 1, "the root catalog should not be modified")
+	assert.Len(t, c.Metadata.MappingReferences, 1, "the root catalog should not be modified")
+
+	// Imported controls keep their mappings, so the resolved catalog carries their references.
+	_, ok := findMappingReference(resolved.Catalog.Metadata, "CSF")
+	assert.True(t, ok)
+	data, err := Marshal(&resolved.Catalog, FormatYAML)
+	require.NoError(t, err)
+	for _, diagnostic := range resolved.Catalog.Validate() {
+		// The upstream catalog maps OSPS-AC-03 to ScCrd without defining that reference.
+		if diagnostic.Code == CodeUnknownMappingReference && strings.Contains(diagnostic.Message, `"ScCrd"`) {
+			continue
+		}
+		assert.Fail(t, "unexpected diagnostic on the resolved catalog", diagnostic.String())
+	}
+	schemaDiagnostics, err := ValidateAgainstSchema(KindCatalog, data)
+	require.NoError(t, err)
+	assert.Empty(t, schemaDiagnostics)
+}
+
+func TestCatalog_Resolve(t *testing.T) {
+	metadata := func(refs ...string) Metadata {
+		m := Metadata{Id: "root"}
+		for _, ref := range refs {
+			m.MappingReferences = append(m.MappingReferences, MappingReference{Id: ref, Title: ref, Version: "1"})
+		}
+		return m
+	}
+	imports := func(ref string, ids ...string) []MultiMapping {
+		mapping := MultiMapping{ReferenceId: ref}
+		for _, id := range ids {
+			mapping.Entries = append(mapping.Entries, MappingEntry{ReferenceId: id})
+		}
+		return []MultiMapping{mapping}
+	}
+
+	upstream := map[string]*Catalog{
+		"BASE": {
+			Metadata:     metadata(),
+			Families:     []Family{{Id: "FAM", Title: "Family"}},
+			Controls:     []Control{{Id: "BASE-1", Family: "FAM"}, {Id: "BASE-2", Family: "FAM"}},
+			Threats:      []Threat{{Id: "THR-1"}},
+			Capabilities: []Capability{{Id: "CAP-1"}},
+		},
+		"MIDDLE": {
+			Metadata:         metadata("BASE"),
+			Controls:         []Control{{Id: "MID-1"}},
+			ImportedControls: imports("BASE", "BASE-2"),
+		},
+		"LOOP": {
+			Metadata:         metadata("LOOP"),
+			ImportedControls: imports("LOOP", "X"),
+		},
+	}
+	fetches := map[string]int{}
+	fetch := func(reference MappingReference) (*Catalog, error) {
+		fetches[reference.Id]++
+		if c, ok := upstream[reference.Id]; ok {
+			return c, nil
+		}
+		return nil, fmt.Errorf("not found")
+	}
+
+	tests := []struct {
+		name         string
+		catalog      *Catalog
+		wantErr      string
+		wantControls []string
+		wantSources  map[string]SingleMapping
+	}{
+		{
+			name: "Threats and capabilities",
+			catalog: &Catalog{
+				Metadata:             metadata("BASE"),
+				ImportedThreats:      imports("BASE", "THR-1"),
+				ImportedCapabilities: imports("BASE", "CAP-1"),
+			},
+			wantSources: map[string]SingleMapping{},
+		},
+		{
+			name: "Transitive imports keep original provenance",
+			catalog: &Catalog{
+				Metadata:         metadata("MIDDLE"),
+				ImportedControls: imports("MIDDLE", "MID-1", "BASE-2"),
+			},
+			wantControls: []string{"MID-1", "BASE-2"},
+			wantSources: map[string]SingleMapping{
+				"MID-1":  {ReferenceId: "MIDDLE", EntryId: "MID-1"},
+				"BASE-2": {ReferenceId: "BASE", EntryId: "BASE-2"},
+			},
+		},
+		{
+			name:    "Missing mapping reference",
+			catalog: &Catalog{ImportedControls: imports("BASE", "BASE-1")},
+			wantErr: `no mapping reference found for imported reference-id "BASE"`,
+		},
+		{
+			name: "Missing entry",
+			catalog: &Catalog{
+				Metadata:         metadata("BASE"),
+				ImportedControls: imports("BASE", "BASE-9"),
+			},
+			wantErr: `control "BASE-9" not found in imported catalog "BASE"`,
+		},
+		{
+			name: "Conflicting id",
+			catalog: &Catalog{
+				Metadata:         metadata("BASE"),
+				Controls:         []Control{{Id: "BASE-1"}},
+				ImportedControls: imports("BASE", "BASE-1"),
+			},
+			wantErr: `imported control "BASE-1" conflicts with an existing control`,
+		},
+		{
+			name: "Fetch failure",
+			catalog: &Catalog{
+				Metadata:         metadata("NOWHERE"),
+				ImportedControls: imports("NOWHERE", "X"),
+			},
+			wantErr: "not found",
+		},
+		{
+			name: "Circular import",
+			catalog: &Catalog{
+				Metadata:         metadata("LOOP"),
+				ImportedControls: imports("LOOP", "X"),
+			},
+			wantErr: "circular catalog import",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := tt.catalog.Resolve(fetch)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var ids []string
+			for _, control := range resolved.Catalog.Controls {
+				ids = append(ids, control.Id)
+			}
+			assert.Equal(t, tt.wantControls, ids)
+			assert.Equal(t, tt.wantSources, resolved.ControlSources)
+		})
+	}
+
+	t.Run("Each reference is fetched once", func(t *testing.T) {
+		fetches = map[string]int{}
+		c := &Catalog{
+			Metadata:             metadata("BASE"),
+			ImportedControls:     imports("BASE", "BASE-1"),
+			ImportedThreats:      imports("BASE", "THR-1"),
+			ImportedCapabilities: imports("BASE", "CAP-1"),
+		}
+		resolved, err := c.Resolve(fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 1, fetches["BASE"])
+		assert.Len(t, resolved.Catalog.Threats, 1)
+		assert.Len(t, resolved.Catalog.Capabilities, 1)
+		assert.Equal(t, SingleMapping{ReferenceId: "BASE", EntryId: "CAP-1"}, resolved.CapabilitySources["CAP-1"])
+	})
+}
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ossf/gemara"
+)
+
+// ErrValidationFailed is returned by Validate when a document has error diagnostics.
+var ErrValidationFailed = errors.New("validation failed")
+
+// ErrNotFormatted is returned by Fmt in check mode when a document is not in canonical layout.
+var ErrNotFormatted = errors.New("documents are not formatted")
+
+// ErrEnforcementBlocked is returned by Enforce when the enforcement action reaches the --fail-on threshold.
+var ErrEnforcementBlocked = errors.New("enforcement blocked")
+
+// kindFlag registers the --kind flag used to skip document type detection.
+func kindFlag(cmd *flag.FlagSet) *string {
+	return cmd.String("kind", "", "Document kind (GuidanceDocument, Catalog, Policy or EvaluationLog); detected when empty")
+}
+
+// outputFlags registers the --output and --format flags shared by commands that emit documents.
+func outputFlags(cmd *flag.FlagSet) (output *string, format *string) {
+	output = cmd.String("output", "", "Path to output file; defaults to stdout")
+	format = cmd.String("format", "", "Output format (yaml or json); defaults to the output file extension, or yaml")
+	return output, format
+}
+
+// readDocument reads the document at path and detects its kind unless one is given.
+func readDocument(path string, kind string) ([]byte, gemara.Kind, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if kind != "" {
+		return data, gemara.Kind(kind), nil
+	}
+	documentKind, err := gemara.DetectKind(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	return data, documentKind, nil
+}
+
+// decodeDocument loads the document at path into the type for kind.
+func decodeDocument(path string, kind gemara.Kind) (gemara.Document, error) {
+	doc, err := gemara.NewDocument(kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// loadDocument reads, detects and decodes the document at path.
+func loadDocument(path string, kind string) (gemara.Document, error) {
+	_, documentKind, err := readDocument(path, kind)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDocument(path, documentKind)
+}
+
+// loadCatalogs loads every catalog in paths.
+func loadCatalogs(paths []string) ([]*gemara.Catalog, error) {
+	var catalogs []*gemara.Catalog
+	for _, path := range paths {
+		catalog := &gemara.Catalog{}
+		if err := catalog.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+			return nil, fmt.Errorf("error loading catalog %s: %w", path, err)
+		}
+		catalogs = append(catalogs, catalog)
+	}
+	return catalogs, nil
+}
+
+// loadPolicies loads every policy in paths.
+func loadPolicies(paths []string) ([]*gemara.Policy, error) {
+	var policies []*gemara.Policy
+	for _, path := range paths {
+		policy := &gemara.Policy{}
+		if err := policy.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+			return nil, fmt.Errorf("error loading policy %s: %w", path, err)
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// writeDocument encodes value in the canonical YAML or JSON layout and writes it to outputFile,
+// or to out when outputFile is empty.
+func writeDocument(out io.Writer, value interface{}, outputFile string, format string) error {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(outputFile), ".")
+	}
+	var documentFormat gemara.Format
+	switch strings.ToLower(format) {
+	case "", "yaml", "yml":
+		documentFormat = gemara.FormatYAML
+	case "json":
+		documentFormat = gemara.FormatJSON
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+	data, err := gemara.Marshal(value, documentFormat)
+	if err != nil {
+		return fmt.Errorf("error encoding output: %w", err)
+	}
+	return writeOutput(out, data, outputFile)
+}
+
+// writeOutput writes data to outputFile, or to out when outputFile is empty.
+func writeOutput(out io.Writer, data []byte, outputFile string) error {
+	if outputFile == "" {
+		_, err := out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outputFile, data, 0600); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Successfully wrote %s\n", outputFile)
+	return err
+}
+
+// stringsFlag is a flag.Value that collects every occurrence of a repeatable flag.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
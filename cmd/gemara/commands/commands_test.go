@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ossf/gemara"
+)
+
+const testData = "../../../test-data"
+
+func TestValidate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var out bytes.Buffer
+		paths := []string{filepath.Join(testData, "good-osps-policy.yaml"), filepath.Join(testData, "good-evaluation-log.yaml")}
+		args := []string{"--catalog", filepath.Join(testData, "good-osps.yml"), "--policy", filepath.Join(testData, "good-osps-policy.yaml")}
+		err := Validate(paths, args, &out)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "good-osps-policy.yaml: valid Policy")
+		assert.Contains(t, out.String(), "good-evaluation-log.yaml: valid EvaluationLog")
+	})
+
+	t.Run("Failure/Schema", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Validate([]string{filepath.Join(testData, "good-ccc.json")}, nil, &out)
+		require.ErrorIs(t, err, ErrValidationFailed)
+		assert.Contains(t, out.String(), "metadata.title [schema-violation] field not allowed")
+	})
+
+	t.Run("Failure/Semantic", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "catalog.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+title: Test
+controls:
+  - id: TEST-01
+    family: MISSING
+    title: Test Control
+    objective: Test objective
+    assessment-requirements: []
+`), 0600))
+		var out bytes.Buffer
+		err := Validate([]string{path}, nil, &out)
+		require.ErrorIs(t, err, ErrValidationFailed)
+		assert.Contains(t, out.String(), path+":5:5: error: controls[0].family [unknown-family]")
+	})
+
+	t.Run("Failure/Decode", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "catalog.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("title: Test\ncontrols: [\n"), 0600))
+		var out bytes.Buffer
+		err := Validate([]string{path}, []string{"--kind", "Catalog"}, &out)
+		require.ErrorIs(t, err, ErrValidationFailed)
+		assert.Contains(t, out.String(), path+":2:11: error: . [unreadable-document] error decoding YAML: ")
+	})
+
+	t.Run("Failure/NotExists", func(t *testing.T) {
+		var out bytes.Buffer
+		paths := []string{"non-existent-file.yaml", filepath.Join(testData, "good-osps-policy.yaml")}
+		err := Validate(paths, nil, &out)
+		require.ErrorIs(t, err, ErrValidationFailed)
+		assert.Contains(t, out.String(), "non-existent-file.yaml: error: . [unreadable-document] open non-existent-file.yaml: no such file or directory")
+		assert.Contains(t, out.String(), "good-osps-policy.yaml: valid Policy")
+	})
+}
+
+func TestConvert(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "evaluation-log.json")
+	var out bytes.Buffer
+	require.NoError(t, Convert(filepath.Join(testData, "good-evaluation-log.yaml"), []string{"--output", output}, &out))
+	assert.Contains(t, out.String(), "Successfully wrote")
+
+	converted := &gemara.EvaluationLog{}
+	require.NoError(t, converted.LoadFile("file://"+output))
+	original := &gemara.EvaluationLog{}
+	require.NoError(t, original.LoadFile("file://"+filepath.Join(testData, "good-evaluation-log.yaml")))
+	assert.Equal(t, original.Metadata, converted.Metadata)
+	require.Len(t, converted.Evaluations, len(original.Evaluations))
+	assert.Equal(t, original.Evaluations[1].Result, converted.Evaluations[1].Result)
+
+	err := Convert(filepath.Join(testData, "good-osps.yml"), []string{"--format", "toml"}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "unsupported output format: toml")
+}
+
+func TestResolve(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Resolve(filepath.Join(testData, "good-imported-catalog.yaml"), []string{"--format", "json"}, &out))
+	var catalog gemara.Catalog
+	require.NoError(t, json.Unmarshal(out.Bytes(), &catalog))
+	assert.Len(t, catalog.Controls, 3)
+	assert.Empty(t, catalog.ImportedControls)
+
+	out.Reset()
+	require.NoError(t, Resolve(filepath.Join(testData, "good-osps-policy.yaml"), nil, &out))
+	assert.Contains(t, out.String(), "EX-AC-03.03")
+	assert.NotContains(t, out.String(), "OSPS-AC-04")
+
+	out.Reset()
+	cacheDir := t.TempDir()
+	require.NoError(t, Resolve(filepath.Join(testData, "good-imported-catalog.yaml"), []string{"--cache-dir", cacheDir, "--offline"}, &out),
+		"local mapping references should not need the cache")
+
+	err := Resolve(filepath.Join(testData, "good-imported-catalog.yaml"), []string{"--offline"}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "--offline requires --cache-dir")
+
+	err = Resolve(filepath.Join(testData, "good-evaluation-log.yaml"), nil, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "resolve does not support EvaluationLog documents")
+}
+
+func TestResolve_OtherDirectory(t *testing.T) {
+	// Relative mapping reference urls are resolved against the document, not the working directory.
+	document, err := filepath.Abs(filepath.Join(testData, "good-imported-catalog.yaml"))
+	require.NoError(t, err)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var out bytes.Buffer
+	require.NoError(t, Resolve(document, []string{"--format", "json"}, &out))
+	var catalog gemara.Catalog
+	require.NoError(t, json.Unmarshal(out.Bytes(), &catalog))
+	assert.Len(t, catalog.Controls, 3)
+}
+
+func TestSARIF(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"--catalog", filepath.Join(testData, "good-osps.yml"), "--artifact-uri", "README.md"}
+	require.NoError(t, SARIF(filepath.Join(testData, "good-evaluation-log.yaml"), args, &out))
+
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "2.1.0", report["version"])
+}
+
+func TestOSCAL(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, OSCAL(filepath.Join(testData, "good-osps.yml"), []string{"--output", output}))
+	_, err := os.Stat(output)
+	require.NoError(t, err)
+
+	err = OSCAL(filepath.Join(testData, "good-evaluation-log.yaml"), nil)
+	assert.ErrorContains(t, err, "oscal export does not support EvaluationLog documents")
+}
+
+func TestRender(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Render(filepath.Join(testData, "good-osps-policy.yaml"), nil, &out))
+	assert.Contains(t, out.String(), "# Policy Checklist: Example Org Open Source Repository Policy")
+	assert.Contains(t, out.String(), "- [ ] ")
+}
+
+func TestFmt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("title: Test\nmetadata:\n  id: TEST\ncontrols: []\n"), 0600))
+
+	var out bytes.Buffer
+	err := Fmt([]string{path}, []string{"--check"}, &out)
+	require.ErrorIs(t, err, ErrNotFormatted)
+	assert.Equal(t, path+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, Fmt([]string{path}, nil, &out))
+	assert.Equal(t, path+"\n", out.String())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "metadata:\n  id: TEST\ntitle: Test\ncontrols: []\n", string(data))
+
+	out.Reset()
+	require.NoError(t, Fmt([]string{path}, []string{"--check"}, &out))
+	assert.Empty(t, out.String())
+}
+
+func TestFmt_ReportsEveryDocument(t *testing.T) {
+	dir := t.TempDir()
+	unformatted := filepath.Join(dir, "catalog.yaml")
+	require.NoError(t, os.WriteFile(unformatted, []byte("title: Test\nmetadata:\n  id: TEST\ncontrols: []\n"), 0600))
+	missing := filepath.Join(dir, "missing.yaml")
+	unknown := filepath.Join(dir, "unknown.yaml")
+	require.NoError(t, os.WriteFile(unknown, []byte("name: not a gemara document\n"), 0600))
+
+	var out bytes.Buffer
+	err := Fmt([]string{missing, unformatted, unknown}, []string{"--check"}, &out)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, missing)
+	assert.ErrorContains(t, err, unknown)
+	assert.Equal(t, unformatted+"\n", out.String())
+}
+
+func TestFmt_Fixtures(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Fmt([]string{filepath.Join(testData, "good-osps-policy.yaml")}, []string{"--check"}, &out))
+	assert.Empty(t, out.String())
+}
+
+func TestEnforce(t *testing.T) {
+	logPath := filepath.Join(testData, "good-evaluation-log.yaml")
+	policyPath := filepath.Join(testData, "good-osps-policy.yaml")
+
+	var out bytes.Buffer
+	err := Enforce(logPath, []string{"--policy", policyPath, "--at", "2025-07-01T00:00:00Z"}, &out)
+	require.ErrorIs(t, err, ErrEnforcementBlocked)
+	assert.Contains(t, out.String(), "OSPS-AC-03: Block (control failed")
+	assert.Contains(t, out.String(), "good-evaluation-log.yaml: Block")
+
+	out.Reset()
+	err = Enforce(logPath, []string{"--policy", policyPath, "--at", "2025-05-01T00:00:00Z", "--format", "json"}, &out)
+	require.NoError(t, err)
+	var report gemara.EnforcementReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, gemara.Warn, report.Action)
+
+	err = Enforce(logPath, []string{"--policy", policyPath, "--at", "2025-05-01T00:00:00Z", "--fail-on", "warn"}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrEnforcementBlocked)
+
+	catalogPath := filepath.Join(testData, "good-osps.yml")
+	err = Enforce(logPath, []string{"--policy", policyPath, "--catalog", catalogPath, "--at", "2025-05-01T00:00:00Z"}, &bytes.Buffer{})
+	assert.NoError(t, err)
+
+	err = Enforce(logPath, nil, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "--policy is required")
+}
@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"flag"
+	"io"
+)
+
+// Convert re-encodes a document as YAML or JSON.
+func Convert(path string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("convert", flag.ExitOnError)
+	kind := kindFlag(cmd)
+	output, format := outputFlags(cmd)
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	doc, err := loadDocument(path, *kind)
+	if err != nil {
+		return err
+	}
+	return writeDocument(out, doc, *output, *format)
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ossf/gemara"
+)
+
+// Enforce decides the enforcement action for every control evaluation in an evaluation log
+// according to a policy, and writes the decisions to out, or the full report when an output
+// file or format is given. ErrEnforcementBlocked is returned when the overall action is at
+// least as severe as the --fail-on action, so release pipelines can gate on the exit code.
+func Enforce(path string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("enforce", flag.ExitOnError)
+	policyPath := cmd.String("policy", "", "Policy to enforce (required)")
+	failOn := cmd.String("fail-on", "block", "Least severe action that fails the command (warn, remediate or block)")
+	at := cmd.String("at", "", "RFC 3339 time used to place the decisions in the enforcement timeline; defaults to now")
+	var catalogPaths stringsFlag
+	cmd.Var(&catalogPaths, "catalog", "Catalog defining the evaluated controls, needed when the policy accepts risks; may be repeated")
+	output, format := outputFlags(cmd)
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+	if *policyPath == "" {
+		return fmt.Errorf("--policy is required")
+	}
+	threshold, err := gemara.ParseEnforcementAction(*failOn)
+	if err != nil || threshold == gemara.Allow {
+		return fmt.Errorf("unsupported --fail-on action: %s", *failOn)
+	}
+	var opts []gemara.EnforcementOption
+	if *at != "" {
+		t, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			return fmt.Errorf("invalid --at time: %w", err)
+		}
+		opts = append(opts, gemara.WithEnforcementTime(t))
+	}
+
+	log := &gemara.EvaluationLog{}
+	if err := log.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+		return err
+	}
+	policies, err := loadPolicies([]string{*policyPath})
+	if err != nil {
+		return err
+	}
+	catalogs, err := loadCatalogs(catalogPaths)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, gemara.WithEnforcementCatalogs(catalogs...))
+	report, err := gemara.Enforce(log, policies[0], opts...)
+	if err != nil {
+		return err
+	}
+
+	if *output != "" || *format != "" {
+		if err := writeDocument(out, report, *output, *format); err != nil {
+			return err
+		}
+	} else {
+		for _, decision := range report.Decisions {
+			if _, err := fmt.Fprintf(out, "%s: %s (%s)\n", decision.Control.EntryId, decision.Action, decision.Rationale); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(out, "%s: %s\n", path, report.Action); err != nil {
+			return err
+		}
+	}
+	if report.Action >= threshold {
+		return ErrEnforcementBlocked
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ossf/gemara"
+	"github.com/ossf/gemara/cmd/oscal_export/export"
+	"github.com/ossf/gemara/sarif"
+)
+
+// OSCAL exports a guidance document or catalog as OSCAL.
+// The flags are those of the oscal_export guidance and catalog subcommands.
+func OSCAL(path string, args []string) error {
+	_, documentKind, err := readDocument(path, "")
+	if err != nil {
+		return err
+	}
+	switch documentKind {
+	case gemara.KindGuidanceDocument:
+		return export.Guidance(path, args)
+	case gemara.KindCatalog:
+		return export.Catalog(path, args)
+	default:
+		return fmt.Errorf("oscal export does not support %s documents", documentKind)
+	}
+}
+
+// SARIF exports an evaluation log as a SARIF report.
+func SARIF(path string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("sarif", flag.ExitOnError)
+	output := cmd.String("output", "", "Path to output file; defaults to stdout")
+	catalogPath := cmd.String("catalog", "", "Catalog used to enrich results with requirement text and recommendations")
+	artifactURI := cmd.String("artifact-uri", "", "File path or URI of the artifact the results apply to")
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	evaluationLog := &gemara.EvaluationLog{}
+	if err := evaluationLog.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+		return err
+	}
+	var catalog *gemara.Catalog
+	if *catalogPath != "" {
+		catalogs, err := loadCatalogs([]string{*catalogPath})
+		if err != nil {
+			return err
+		}
+		catalog = catalogs[0]
+	}
+
+	report, err := sarif.FromEvaluationLog(*evaluationLog, *artifactURI, catalog)
+	if err != nil {
+		return err
+	}
+	return writeOutput(out, append(report, '\n'), *output)
+}
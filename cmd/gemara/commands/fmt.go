@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ossf/gemara"
+)
+
+// Fmt rewrites each document in the canonical layout produced by gemara.FormatDocument and
+// writes the path of every document it changed to out. With --check, documents are left
+// untouched and ErrNotFormatted is returned when any of them is not in canonical layout.
+// A document that cannot be read or formatted does not stop the others; the errors of
+// every such document are returned together.
+func Fmt(paths []string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("fmt", flag.ExitOnError)
+	check := cmd.Bool("check", false, "List documents that are not formatted instead of rewriting them")
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	unformatted := false
+	var errs []error
+	for _, path := range paths {
+		changed, err := fmtFile(path, *check)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		unformatted = true
+		if _, err := fmt.Fprintln(out, path); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if *check && unformatted {
+		return ErrNotFormatted
+	}
+	return nil
+}
+
+// fmtFile reports whether the document at path is not in canonical layout, rewriting it
+// unless check is set.
+func fmtFile(path string, check bool) (bool, error) {
+	format, err := gemara.FormatFromPath(path)
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	formatted, err := gemara.FormatDocument(data, format)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(data, formatted) {
+		return false, nil
+	}
+	if !check {
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ossf/gemara"
+)
+
+// Render writes the Markdown checklist for a policy.
+func Render(path string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("render", flag.ExitOnError)
+	output := cmd.String("output", "", "Path to output file; defaults to stdout")
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	policy := &gemara.Policy{}
+	if err := policy.LoadFile(fmt.Sprintf("file://%s", path)); err != nil {
+		return err
+	}
+	checklist, err := policy.ToMarkdownChecklist()
+	if err != nil {
+		return err
+	}
+	return writeOutput(out, []byte(checklist), *output)
+}
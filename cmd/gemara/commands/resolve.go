@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ossf/gemara"
+)
+
+// Resolve materializes the imports of a catalog or policy.
+// A catalog is written with its imported controls, threats and capabilities inlined.
+// A policy is written as its effective catalog, or as its effective guidance with --guidance.
+// Relative file urls in mapping references are resolved against the directory of the document.
+func Resolve(path string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("resolve", flag.ExitOnError)
+	kind := kindFlag(cmd)
+	guidance := cmd.Bool("guidance", false, "Resolve the guidance imports of a policy instead of its catalog imports")
+	cacheDir := cmd.String("cache-dir", "", "Directory mirroring remote documents by host and path; fetched documents are stored there")
+	offline := cmd.Bool("offline", false, "Only serve remote documents from --cache-dir")
+	output, format := outputFlags(cmd)
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+
+	if *offline && *cacheDir == "" {
+		return fmt.Errorf("--offline requires --cache-dir")
+	}
+	fetcher := gemara.DefaultFetcher
+	if *cacheDir != "" {
+		fetcher = gemara.CacheFetcher{Dir: *cacheDir, Offline: *offline}
+	}
+
+	doc, err := loadDocument(path, *kind)
+	if err != nil {
+		return err
+	}
+	// Relative mapping reference urls are relative to the document that declares them.
+	fetcher = gemara.RelativeFetcher(filepath.Dir(path), fetcher)
+
+	var resolved interface{}
+	switch doc := doc.(type) {
+	case *gemara.Catalog:
+		catalog, err := doc.Resolve(gemara.NewCatalogFetcher(fetcher))
+		if err != nil {
+			return err
+		}
+		resolved = &catalog.Catalog
+	case *gemara.Policy:
+		if *guidance {
+			effective, err := doc.ResolveGuidance(gemara.NewGuidanceFetcher(fetcher))
+			if err != nil {
+				return err
+			}
+			resolved = effective
+			break
+		}
+		effective, err := doc.ResolveCatalogs(gemara.NewCatalogFetcher(fetcher))
+		if err != nil {
+			return err
+		}
+		resolved = &effective.Catalog
+	default:
+		return fmt.Errorf("resolve does not support %s documents", doc.Kind())
+	}
+	return writeDocument(out, resolved, *output, *format)
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/ossf/gemara"
+)
+
+// Validate checks each document against its schema definition and, when the schema
+// accepts it, runs the semantic checks for its kind. Diagnostics are written to out.
+// A document that cannot be read or decoded is reported with an unreadable-document
+// diagnostic and the remaining documents are still validated. ErrValidationFailed is
+// returned when any document has an error diagnostic.
+func Validate(paths []string, args []string, out io.Writer) error {
+	cmd := flag.NewFlagSet("validate", flag.ExitOnError)
+	kind := kindFlag(cmd)
+	var catalogPaths stringsFlag
+	cmd.Var(&catalogPaths, "catalog", "Catalog referenced by a policy or evaluation log; may be repeated")
+	var policyPaths stringsFlag
+	cmd.Var(&policyPaths, "policy", "Policy whose assessment plans an evaluation log executes; may be repeated")
+	if err := cmd.Parse(args); err != nil {
+		return err
+	}
+	catalogs, err := loadCatalogs(catalogPaths)
+	if err != nil {
+		return err
+	}
+	policies, err := loadPolicies(policyPaths)
+	if err != nil {
+		return err
+	}
+	opts := []gemara.ValidateOption{gemara.WithCatalogs(catalogs...), gemara.WithPolicies(policies...)}
+
+	failed := false
+	for _, path := range paths {
+		diagnostics, documentKind, err := validateFile(path, *kind, opts)
+		if err != nil {
+			diagnostics = gemara.Diagnostics{unreadable(err)}
+		}
+		for _, diagnostic := range diagnostics {
+			line := diagnostic.String()
+			if !diagnostic.Position.IsValid() {
+				line = path + ": " + line
+			}
+			if _, err := fmt.Fprintln(out, line); err != nil {
+				return err
+			}
+		}
+		if diagnostics.HasErrors() {
+			failed = true
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s: valid %s\n", path, documentKind); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return ErrValidationFailed
+	}
+	return nil
+}
+
+func validateFile(path string, kind string, opts []gemara.ValidateOption) (gemara.Diagnostics, gemara.Kind, error) {
+	data, documentKind, err := readDocument(path, kind)
+	if err != nil {
+		return nil, "", err
+	}
+	doc, err := gemara.NewDocument(documentKind)
+	if err != nil {
+		return nil, "", err
+	}
+	positions, decodeErr := gemara.DecodeWithPositions(gemara.FromBytes(path, data), doc)
+	if decodeErr != nil && positions == nil {
+		return nil, "", decodeErr
+	}
+	diagnostics, err := gemara.ValidateAgainstSchema(documentKind, data)
+	if err != nil {
+		return nil, "", err
+	}
+	if diagnostics.HasErrors() {
+		return positions.Annotate(diagnostics), documentKind, nil
+	}
+	if decodeErr != nil {
+		return nil, "", decodeErr
+	}
+
+	opts = append(opts[:len(opts):len(opts)], gemara.WithPositions(positions))
+	return append(positions.Annotate(diagnostics), doc.Validate(opts...)...), documentKind, nil
+}
+
+// unreadable reports a document that could not be read or decoded, at the position of the
+// problem when it is known.
+func unreadable(err error) gemara.Diagnostic {
+	diagnostic := gemara.Diagnostic{
+		Code:     gemara.CodeUnreadableDocument,
+		Severity: gemara.SeverityError,
+		Path:     ".",
+		Message:  err.Error(),
+	}
+	var decodeErr *gemara.DecodeError
+	if errors.As(err, &decodeErr) {
+		diagnostic.Position = decodeErr.Position
+		if decodeErr.Path != "" {
+			diagnostic.Path = decodeErr.Path
+		}
+		diagnostic.Message = decodeErr.Err.Error()
+	}
+	return diagnostic
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ossf/gemara/cmd/gemara/commands"
+)
+
+const usage = `Usage: gemara <command> <path>... [flags]
+
+Commands:
+  validate <path>...        Validate documents against the schema and semantic rules
+  fmt <path>...             Rewrite documents in canonical layout, or list them with --check
+  convert <path>            Convert a document between YAML and JSON
+  resolve <path>            Resolve the imports of a catalog or policy
+  export oscal <path>       Export a guidance document or catalog as OSCAL
+  export sarif <path>       Export an evaluation log as SARIF
+  render <path>             Render a policy as a Markdown checklist
+  enforce <path>            Decide the enforcement actions of an evaluation log under a policy
+
+Run 'gemara <command> <path> -h' for the flags of a command.
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	command, args := args[0], args[1:]
+	if command == "export" {
+		if len(args) < 1 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		command, args = command+" "+args[0], args[1:]
+	}
+	paths, flags := splitArgs(args)
+	if command == "fmt" {
+		paths, flags = splitBoolArgs(args)
+	}
+	if len(paths) < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	if command != "validate" && command != "fmt" && len(paths) > 1 {
+		fmt.Fprintf(os.Stderr, "%s accepts a single path\n", command)
+		os.Exit(1)
+	}
+
+	var err error
+	switch command {
+	case "validate":
+		err = commands.Validate(paths, flags, os.Stdout)
+	case "fmt":
+		err = commands.Fmt(paths, flags, os.Stdout)
+	case "convert":
+		err = commands.Convert(paths[0], flags, os.Stdout)
+	case "resolve":
+		err = commands.Resolve(paths[0], flags, os.Stdout)
+	case "export oscal":
+		err = commands.OSCAL(paths[0], flags)
+	case "export sarif":
+		err = commands.SARIF(paths[0], flags, os.Stdout)
+	case "render":
+		err = commands.Render(paths[0], flags, os.Stdout)
+	case "enforce":
+		err = commands.Enforce(paths[0], flags, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if errors.Is(err, commands.ErrValidationFailed) || errors.Is(err, commands.ErrNotFormatted) ||
+		errors.Is(err, commands.ErrEnforcementBlocked) {
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error processing command: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// splitArgs separates the leading paths from the flags that follow them.
+func splitArgs(args []string) (paths []string, flags []string) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i], args[i:]
+		}
+	}
+	return args, nil
+}
+
+// splitBoolArgs separates paths from flags wherever they appear, for commands whose flags
+// take no value.
+func splitBoolArgs(args []string) (paths []string, flags []string) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			paths = append(paths, arg)
+		}
+	}
+	return paths, flags
+}
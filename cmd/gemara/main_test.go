@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	paths, flags := splitArgs([]string{"policy.yaml", "--catalog", "catalog.yaml"})
+	assert.Equal(t, []string{"policy.yaml"}, paths)
+	assert.Equal(t, []string{"--catalog", "catalog.yaml"}, flags)
+
+	paths, flags = splitBoolArgs([]string{"--check", "catalog.yaml", "policy.yaml"})
+	assert.Equal(t, []string{"catalog.yaml", "policy.yaml"}, paths)
+	assert.Equal(t, []string{"--check"}, flags)
+}
@@ -1,6 +1,11 @@
 package gemara
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
 
 // ConfidenceLevel indicates the evaluator's confidence level in an assessment result.
 // This is designed to restrict the possible confidence level values to a set of known levels.
@@ -27,6 +32,14 @@ var confidenceLevelToString = map[ConfidenceLevel]string{
 	High:         "High",
 }
 
+var stringToConfidenceLevel = map[string]ConfidenceLevel{
+	"Not Set":      NotSet,
+	"Undetermined": Undetermined,
+	"Low":          Low,
+	"Medium":       Medium,
+	"High":         High,
+}
+
 func (c ConfidenceLevel) String() string {
 	return confidenceLevelToString[c]
 }
@@ -36,7 +49,33 @@ func (c ConfidenceLevel) MarshalYAML() (interface{}, error) {
 	return c.String(), nil
 }
 
+// UnmarshalYAML ensures that ConfidenceLevel can be deserialized from a YAML string
+func (c *ConfidenceLevel) UnmarshalYAML(data []byte) error {
+	var s string
+	if err := loaders.UnmarshalYAML(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToConfidenceLevel[s]; ok {
+		*c = val
+		return nil
+	}
+	return fmt.Errorf("invalid ConfidenceLevel: %s", s)
+}
+
 // MarshalJSON ensures that ConfidenceLevel is serialized as a string in JSON
 func (c ConfidenceLevel) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.String())
 }
+
+// UnmarshalJSON ensures that ConfidenceLevel can be deserialized from a JSON string
+func (c *ConfidenceLevel) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToConfidenceLevel[s]; ok {
+		*c = val
+		return nil
+	}
+	return fmt.Errorf("invalid ConfidenceLevel: %s", s)
+}
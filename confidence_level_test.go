@@ -150,3 +150,92 @@ func TestConfidenceLevel_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestConfidenceLevel_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ConfidenceLevel
+		wantErr bool
+	}{
+		{
+			name: "Not Set level",
+			data: "Not Set",
+			want: NotSet,
+		},
+		{
+			name: "Undetermined level",
+			data: "Undetermined",
+			want: Undetermined,
+		},
+		{
+			name: "High level",
+			data: "High",
+			want: High,
+		},
+		{
+			name:    "Invalid level",
+			data:    "Very High",
+			want:    NotSet,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var level ConfidenceLevel
+			err := level.UnmarshalYAML([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, level)
+		})
+	}
+}
+
+func TestConfidenceLevel_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    ConfidenceLevel
+		wantErr bool
+	}{
+		{
+			name: "Low level",
+			data: `"Low"`,
+			want: Low,
+		},
+		{
+			name: "Medium level",
+			data: `"Medium"`,
+			want: Medium,
+		},
+		{
+			name:    "Invalid level",
+			data:    `"medium"`,
+			want:    NotSet,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			data:    `not json`,
+			want:    NotSet,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var level ConfidenceLevel
+			err := level.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, level)
+		})
+	}
+}
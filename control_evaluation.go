@@ -1,5 +1,10 @@
 package gemara
 
+import (
+	"context"
+	"fmt"
+)
+
 // AddAssessment creates a new AssessmentLog object and adds it to the ControlEvaluation.
 func (c *ControlEvaluation) AddAssessment(requirementId string, description string, applicability []string, steps []AssessmentStep) (assessment *AssessmentLog) {
 	assessment, err := NewAssessment(requirementId, description, applicability, steps)
@@ -16,27 +21,49 @@ func (c *ControlEvaluation) AddAssessment(requirementId string, description stri
 // The userApplicability is a slice of strings that determine when the assessment is applicable. The changesAllowed
 // determines whether the assessment is allowed to execute its changes.
 func (c *ControlEvaluation) Evaluate(targetData interface{}, userApplicability []string) {
+	c.EvaluateContext(context.Background(), targetData, userApplicability)
+}
+
+// EvaluateContext runs each applicable assessment like Evaluate, using AssessmentLog.RunContext
+// with ctx and opts. Once ctx is cancelled, the remaining assessments are not run.
+// Assessments that list none of userApplicability are marked NotApplicable, as by EvaluateMatching.
+// When userApplicability is empty, no assessment is run or marked, so they remain NotRun and the
+// control is not reported as compliant.
+func (c *ControlEvaluation) EvaluateContext(ctx context.Context, targetData interface{}, userApplicability []string, opts ...RunOption) {
+	if len(userApplicability) == 0 {
+		if len(c.AssessmentLogs) == 0 {
+			c.Result = NeedsReview
+		}
+		return
+	}
+	c.EvaluateMatching(ctx, targetData, AnyOf(userApplicability...), opts...)
+}
+
+// EvaluateMatching runs the assessments whose applicability satisfies matcher, like EvaluateContext.
+// Assessments that do not apply are marked NotApplicable; when none apply, neither does the control.
+func (c *ControlEvaluation) EvaluateMatching(ctx context.Context, targetData interface{}, matcher ApplicabilityMatcher, opts ...RunOption) {
 	if len(c.AssessmentLogs) == 0 {
 		c.Result = NeedsReview
 		return
 	}
 	for _, assessment := range c.AssessmentLogs {
-		var applicable bool
-		for _, aa := range assessment.Applicability {
-			for _, ua := range userApplicability {
-				if aa == ua {
-					applicable = true
-					break
-				}
+		if !matcher.Matches(assessment.Applicability) {
+			assessment.Result = NotApplicable
+			assessment.Message = fmt.Sprintf("applicability %v does not satisfy %s", assessment.Applicability, matcher)
+			c.Result = UpdateAggregateResult(c.Result, NotApplicable)
+			if c.Result == NotApplicable {
+				c.Message = assessment.Message
 			}
+			continue
 		}
-		if applicable {
-			result := assessment.Run(targetData)
-			c.Result = UpdateAggregateResult(c.Result, result)
-			c.Message = assessment.Message
-			if c.Result == Failed {
-				break
-			}
+		if ctx.Err() != nil {
+			break
+		}
+		result := assessment.RunContext(ctx, targetData, opts...)
+		c.Result = UpdateAggregateResult(c.Result, result)
+		c.Message = assessment.Message
+		if c.Result == Failed {
+			break
 		}
 	}
 }
@@ -1,6 +1,7 @@
 package gemara
 
 import (
+	"context"
 	"testing"
 )
 
@@ -125,3 +126,40 @@ func TestAddAssesment(t *testing.T) {
 	}
 
 }
+
+func TestEvaluateContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	control := &ControlEvaluation{
+		AssessmentLogs: []*AssessmentLog{passingAssessmentPtr(), failingAssessmentPtr()},
+	}
+	control.EvaluateContext(ctx, nil, []string{"test-applicability"})
+	for _, assessment := range control.AssessmentLogs {
+		if assessment.StepsExecuted != 0 {
+			t.Errorf("expected no steps to run after cancellation, got %d", assessment.StepsExecuted)
+		}
+	}
+}
+
+func TestEvaluateContext_NoApplicability(t *testing.T) {
+	control := &ControlEvaluation{
+		AssessmentLogs: []*AssessmentLog{passingAssessmentPtr(), failingAssessmentPtr()},
+	}
+	control.EvaluateContext(context.Background(), nil, nil)
+	if control.Result != NotRun {
+		t.Errorf("Expected Result to be %v, but it was %v", NotRun, control.Result)
+	}
+	for _, assessment := range control.AssessmentLogs {
+		if assessment.Result != NotRun {
+			t.Errorf("Expected assessment Result to be %v, but it was %v", NotRun, assessment.Result)
+		}
+	}
+
+	log := &EvaluationLog{Evaluations: []*ControlEvaluation{{
+		AssessmentLogs: []*AssessmentLog{passingAssessmentPtr()},
+	}}}
+	log.EvaluateContext(context.Background(), nil, []string{})
+	if log.Evaluations[0].Result != NotRun {
+		t.Errorf("Expected Result to be %v, but it was %v", NotRun, log.Evaluations[0].Result)
+	}
+}
@@ -0,0 +1,117 @@
+package gemara
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Kind identifies the type of a Gemara document by the name of its schema definition.
+type Kind string
+
+// Kinds of documents defined by the Gemara schemas.
+const (
+	KindGuidanceDocument Kind = "GuidanceDocument"
+	KindCatalog          Kind = "Catalog"
+	KindPolicy           Kind = "Policy"
+	KindEvaluationLog    Kind = "EvaluationLog"
+)
+
+// Document is implemented by every top-level Gemara document type, allowing
+// tooling to handle a mix of artifacts without knowing their types in advance.
+type Document interface {
+	// Kind returns the kind of the document.
+	Kind() Kind
+	// GetMetadata returns the document metadata.
+	GetMetadata() Metadata
+	// LoadFile loads the document from a YAML or JSON file or https URI.
+	LoadFile(sourcePath string) error
+	// Validate checks the document for semantic problems.
+	Validate(opts ...ValidateOption) Diagnostics
+	// Save writes the document to a file URI or plain path in the given format.
+	Save(uri string, format Format, opts ...SaveOption) error
+}
+
+var (
+	_ Document = (*GuidanceDocument)(nil)
+	_ Document = (*Catalog)(nil)
+	_ Document = (*Policy)(nil)
+	_ Document = (*EvaluationLog)(nil)
+)
+
+func (g *GuidanceDocument) Kind() Kind { return KindGuidanceDocument }
+
+func (c *Catalog) Kind() Kind { return KindCatalog }
+
+func (p *Policy) Kind() Kind { return KindPolicy }
+
+func (e *EvaluationLog) Kind() Kind { return KindEvaluationLog }
+
+func (g *GuidanceDocument) GetMetadata() Metadata { return g.Metadata }
+
+func (c *Catalog) GetMetadata() Metadata { return c.Metadata }
+
+func (p *Policy) GetMetadata() Metadata { return p.Metadata }
+
+func (e *EvaluationLog) GetMetadata() Metadata { return e.Metadata }
+
+// NewDocument returns an empty document of the given kind.
+func NewDocument(kind Kind) (Document, error) {
+	switch kind {
+	case KindGuidanceDocument:
+		return &GuidanceDocument{}, nil
+	case KindCatalog:
+		return &Catalog{}, nil
+	case KindPolicy:
+		return &Policy{}, nil
+	case KindEvaluationLog:
+		return &EvaluationLog{}, nil
+	}
+	return nil, fmt.Errorf("unsupported document kind: %s", kind)
+}
+
+// Load loads a document of any kind from a YAML or JSON file at the provided path.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+// The kind is detected from the top-level fields of the document, as described by DetectKind.
+func Load(sourcePath string) (Document, error) {
+	if _, err := FormatFromPath(sourcePath); err != nil {
+		return nil, err
+	}
+	// The document is retrieved once and decoded twice from the same bytes, so a remote
+	// document cannot change between detecting its kind and decoding it.
+	return LoadSource(FromURI(sourcePath, DefaultFetcher))
+}
+
+// DetectKind infers the kind of a YAML or JSON document from its top-level fields.
+// Evaluation logs are recognized by evaluations, policies by adherence, contacts or scope,
+// guidance documents by document-type, guidelines or exemptions, and catalogs by controls,
+// threats, capabilities or their imports.
+func DetectKind(data []byte) (Kind, error) {
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("error decoding document: %w", err)
+	}
+	return detectKind(fields)
+}
+
+func detectKind(fields map[string]interface{}) (Kind, error) {
+	has := func(keys ...string) bool {
+		for _, key := range keys {
+			if _, ok := fields[key]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("evaluations"):
+		return KindEvaluationLog, nil
+	case has("adherence", "contacts", "scope"):
+		return KindPolicy, nil
+	case has("document-type", "guidelines", "exemptions"):
+		return KindGuidanceDocument, nil
+	case has("controls", "threats", "capabilities", "imported-controls", "imported-threats", "imported-capabilities"):
+		return KindCatalog, nil
+	}
+	return "", fmt.Errorf("unable to detect document kind")
+}
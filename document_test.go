@@ -0,0 +1,90 @@
+package gemara
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourcePath string
+		wantKind   Kind
+		wantId     string
+	}{
+		{"Guidance YAML", "file://test-data/good-aigf.yaml", KindGuidanceDocument, "FINOS-AIR"},
+		{"Catalog YAML", "file://test-data/good-osps.yml", KindCatalog, "OSPS-B"},
+		{"Policy YAML", "file://test-data/good-osps-policy.yaml", KindPolicy, "example-org-osps-policy"},
+		{"EvaluationLog YAML", "file://test-data/good-evaluation-log.yaml", KindEvaluationLog, "osps-baseline-evaluation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Load(tt.sourcePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKind, doc.Kind())
+			assert.Equal(t, tt.wantId, doc.GetMetadata().Id)
+		})
+	}
+}
+
+func TestLoad_Typed(t *testing.T) {
+	doc, err := Load("file://test-data/good-osps-policy.yaml")
+	require.NoError(t, err)
+	policy, ok := doc.(*Policy)
+	require.True(t, ok)
+	assert.Len(t, policy.Adherence.AssessmentPlans, 2)
+	assert.Empty(t, doc.Validate())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load("file://test-data/unsupported.txt")
+	assert.ErrorContains(t, err, "unsupported file extension: .txt")
+
+	_, err = Load("file://test-data/nested-empty.yaml")
+	assert.ErrorContains(t, err, "unable to detect document kind")
+}
+
+func TestDetectKind(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Kind
+	}{
+		{"evaluation log", "evaluations: []", KindEvaluationLog},
+		{"policy", "title: x\nadherence: {}", KindPolicy},
+		{"guidance", `{"title": "x", "document-type": "Standard"}`, KindGuidanceDocument},
+		{"catalog", "title: x\ncontrols: []", KindCatalog},
+		{"catalog with imports only", "title: x\nimported-controls: []", KindCatalog},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, err := DetectKind([]byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, kind)
+		})
+	}
+
+	_, err := DetectKind([]byte("title: x"))
+	assert.ErrorContains(t, err, "unable to detect document kind")
+
+	_, err = NewDocument("Checklist")
+	assert.ErrorContains(t, err, "unsupported document kind: Checklist")
+}
+
+func TestLoad_FetchesOnce(t *testing.T) {
+	original := DefaultFetcher
+	t.Cleanup(func() { DefaultFetcher = original })
+	var fetched []string
+	DefaultFetcher = FetcherFunc(func(uri string) (io.ReadCloser, error) {
+		fetched = append(fetched, uri)
+		return FileFetcher{}.Fetch("test-data/good-osps.yml")
+	})
+
+	doc, err := Load("https://example.com/catalogs/osps.yml")
+	require.NoError(t, err)
+	assert.Equal(t, KindCatalog, doc.Kind())
+	assert.Equal(t, []string{"https://example.com/catalogs/osps.yml"}, fetched)
+}
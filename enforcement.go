@@ -0,0 +1,381 @@
+package gemara
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
+
+// EnforcementAction is an enum representing the Layer 5 action taken on a control evaluation.
+// The actions are ordered by severity, so the most severe of two actions is the greater one.
+type EnforcementAction int
+
+const (
+	// Allow lets the evaluated resource proceed.
+	Allow EnforcementAction = iota
+	// Warn lets the evaluated resource proceed and notifies its owners of the findings.
+	Warn
+	// Remediate requests an automated fix of the findings.
+	Remediate
+	// Block prevents the evaluated resource from proceeding, such as at a deployment gate.
+	Block
+)
+
+var enforcementActionToString = map[EnforcementAction]string{
+	Allow:     "Allow",
+	Warn:      "Warn",
+	Remediate: "Remediate",
+	Block:     "Block",
+}
+
+var stringToEnforcementAction = map[string]EnforcementAction{
+	"Allow":     Allow,
+	"Warn":      Warn,
+	"Remediate": Remediate,
+	"Block":     Block,
+}
+
+func (a EnforcementAction) String() string {
+	return enforcementActionToString[a]
+}
+
+// ParseEnforcementAction returns the action named s, ignoring case.
+func ParseEnforcementAction(s string) (EnforcementAction, error) {
+	for name, action := range stringToEnforcementAction {
+		if strings.EqualFold(name, s) {
+			return action, nil
+		}
+	}
+	return Allow, fmt.Errorf("invalid EnforcementAction: %s", s)
+}
+
+// MarshalYAML ensures that EnforcementAction is serialized as a string in YAML
+func (a EnforcementAction) MarshalYAML() (interface{}, error) {
+	return a.String(), nil
+}
+
+// UnmarshalYAML ensures that EnforcementAction can be deserialized from a YAML string
+func (a *EnforcementAction) UnmarshalYAML(data []byte) error {
+	var s string
+	if err := loaders.UnmarshalYAML(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToEnforcementAction[s]; ok {
+		*a = val
+		return nil
+	}
+	return fmt.Errorf("invalid EnforcementAction: %s", s)
+}
+
+// MarshalJSON ensures that EnforcementAction is serialized as a string in JSON
+func (a EnforcementAction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON ensures that EnforcementAction can be deserialized from a JSON string
+func (a *EnforcementAction) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToEnforcementAction[s]; ok {
+		*a = val
+		return nil
+	}
+	return fmt.Errorf("invalid EnforcementAction: %s", s)
+}
+
+// EnforcementDecision is the action taken on a single control evaluation.
+type EnforcementDecision struct {
+	// Control maps to the evaluated control.
+	Control SingleMapping `json:"control" yaml:"control"`
+
+	// Result is the result of the control evaluation the decision is based on.
+	Result Result `json:"result" yaml:"result"`
+
+	Action EnforcementAction `json:"action" yaml:"action"`
+
+	// Rationale explains why the action was chosen.
+	Rationale string `json:"rationale" yaml:"rationale"`
+}
+
+// EnforcementReport holds the enforcement decisions for every control evaluation in an EvaluationLog.
+type EnforcementReport struct {
+	// Policy is the id of the policy that was enforced.
+	Policy string `json:"policy" yaml:"policy"`
+
+	// EvaluationLog is the id of the evaluation log that was enforced.
+	EvaluationLog string `json:"evaluation-log,omitempty" yaml:"evaluation-log,omitempty"`
+
+	// Decided is the time the decisions apply to, which places them in the enforcement timeline.
+	Decided Datetime `json:"decided" yaml:"decided"`
+
+	// Action is the most severe action of the decisions, or Allow when there are none.
+	Action EnforcementAction `json:"action" yaml:"action"`
+
+	Decisions []EnforcementDecision `json:"decisions" yaml:"decisions"`
+
+	// NonCompliance is the non-compliance procedure of the policy, included when any control is not allowed.
+	NonCompliance string `json:"non-compliance,omitempty" yaml:"non-compliance,omitempty"`
+}
+
+// Decision returns the decision for the control with the given id.
+func (r *EnforcementReport) Decision(controlId string) (EnforcementDecision, bool) {
+	for _, decision := range r.Decisions {
+		if decision.Control.EntryId == controlId {
+			return decision, true
+		}
+	}
+	return EnforcementDecision{}, false
+}
+
+// EnforcementOption defines an option to tune the behavior of Enforce.
+type EnforcementOption func(opts *enforcementOpts)
+
+type enforcementOpts struct {
+	now      time.Time
+	catalogs []*Catalog
+}
+
+// WithEnforcementTime is an EnforcementOption that sets the time used to place the decisions in
+// the enforcement timeline of the policy. It defaults to the current time.
+func WithEnforcementTime(t time.Time) EnforcementOption {
+	return func(opts *enforcementOpts) {
+		opts.now = t
+	}
+}
+
+// WithEnforcementCatalogs is an EnforcementOption that provides the catalogs defining the
+// evaluated controls, whose threat mappings identify the controls addressing the accepted risks
+// of the policy. They are required when the policy accepts risks.
+func WithEnforcementCatalogs(catalogs ...*Catalog) EnforcementOption {
+	return func(opts *enforcementOpts) {
+		opts.catalogs = catalogs
+	}
+}
+
+// Enforce decides the Layer 5 action for every control evaluation in log according to policy.
+// Every evaluated control must map to a catalog the policy imports, so a log cannot be judged
+// against a policy it was not evaluated for. Logs built by BuildEvaluationLog from a catalog the
+// policy imports meet this, as do logs built from the EffectiveCatalog of the policy with
+// WithControlSources.
+//
+// Passed and Not Applicable controls are allowed. Failed controls are acted on with the
+// Adherence.EnforcementMethods of the policy: a "gate" method blocks them, an "autoremediation"
+// method, without a gate, remediates them, and any other method, or none, only warns. Enforcement
+// methods are declared for the whole policy rather than per assessment plan, so the most severe
+// one applies to every failed control. Controls that need review, could not be determined or
+// were not run are warned about, since they neither demonstrate compliance nor prove a violation.
+//
+// A failed control whose threat mappings, in the catalogs provided with WithEnforcementCatalogs,
+// are all risks in Risks.Accepted is only warned about, with the justification of the risks in
+// the rationale. The scope of an accepted risk is not considered.
+//
+// Blocking and remediation only apply within the enforcement timeline of the policy's
+// ImplementationPlan. Before it starts or after it ends, those decisions are downgraded to Warn.
+func Enforce(log *EvaluationLog, policy *Policy, opts ...EnforcementOption) (*EnforcementReport, error) {
+	if log == nil {
+		return nil, fmt.Errorf("no evaluation log to enforce")
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("no policy to enforce")
+	}
+	options := &enforcementOpts{now: time.Now()}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if err := checkEnforcedControls(log, policy); err != nil {
+		return nil, err
+	}
+	if len(policy.Risks.Accepted) > 0 && len(options.catalogs) == 0 {
+		return nil, fmt.Errorf("policy %s accepts risks, which are matched to controls through catalog threat mappings, but no catalogs were provided", policy.Metadata.Id)
+	}
+	active, timeline, err := enforcementActive(policy.ImplementationPlan.EnforcementTimeline, options.now)
+	if err != nil {
+		return nil, err
+	}
+	method, failedAction := failureEnforcement(policy.Adherence.EnforcementMethods)
+
+	report := &EnforcementReport{
+		Policy:        policy.Metadata.Id,
+		EvaluationLog: log.Metadata.Id,
+		Decided:       Datetime(options.now.UTC().Format(time.RFC3339)),
+		Decisions:     []EnforcementDecision{},
+	}
+	for _, evaluation := range log.Evaluations {
+		if evaluation == nil {
+			continue
+		}
+		decision := EnforcementDecision{Control: evaluation.Control, Result: evaluation.Result}
+		if decision.Control.EntryId == "" {
+			decision.Control.EntryId = evaluation.Name
+		}
+		switch evaluation.Result {
+		case Passed:
+			decision.Action = Allow
+			decision.Rationale = "control passed"
+		case NotApplicable:
+			decision.Action = Allow
+			decision.Rationale = "control is not applicable"
+		case Failed:
+			decision.Action = failedAction
+			decision.Rationale = fmt.Sprintf("control failed: %s", evaluation.Message)
+			if method != "" {
+				decision.Rationale += fmt.Sprintf("; enforced by %s", method)
+			}
+			if accepted := acceptedRisks(evaluation.Control, policy.Risks.Accepted, options.catalogs); len(accepted) > 0 {
+				decision.Action = Warn
+				decision.Rationale += fmt.Sprintf("; the risks it addresses are accepted (%s), so the finding is only reported", strings.Join(accepted, "; "))
+			}
+			if decision.Action > Warn && !active {
+				decision.Action = Warn
+				decision.Rationale += fmt.Sprintf("; %s, so the finding is only reported", timeline)
+			}
+		case NotRun:
+			decision.Action = Warn
+			decision.Rationale = "control was not evaluated"
+		default:
+			decision.Action = Warn
+			decision.Rationale = fmt.Sprintf("control result is %s: %s", evaluation.Result, evaluation.Message)
+		}
+		if decision.Action > report.Action {
+			report.Action = decision.Action
+		}
+		report.Decisions = append(report.Decisions, decision)
+	}
+	if report.Action != Allow {
+		report.NonCompliance = policy.Adherence.NonCompliance
+	}
+	return report, nil
+}
+
+// checkEnforcedControls reports the evaluated controls whose reference-id is not one of the
+// catalogs imported by policy.
+func checkEnforcedControls(log *EvaluationLog, policy *Policy) error {
+	imported := make(map[string]bool, len(policy.Imports.Catalogs))
+	for _, catalogImport := range policy.Imports.Catalogs {
+		imported[catalogImport.ReferenceId] = true
+	}
+	var problems []string
+	for _, evaluation := range log.Evaluations {
+		if evaluation == nil || imported[evaluation.Control.ReferenceId] {
+			continue
+		}
+		if evaluation.Control.ReferenceId == "" {
+			problems = append(problems, fmt.Sprintf("control %s has no reference-id", evaluation.Control.EntryId))
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("control %s references %s, which the policy does not import",
+			evaluation.Control.EntryId, evaluation.Control.ReferenceId))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("evaluation log %s does not match policy %s: %s", log.Metadata.Id, policy.Metadata.Id, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// acceptedRisks returns a description of the accepted risks covering control, which is covered
+// when it is mapped to at least one threat and every threat it is mapped to is accepted.
+func acceptedRisks(control SingleMapping, accepted []AcceptedRisk, catalogs []*Catalog) []string {
+	if len(accepted) == 0 {
+		return nil
+	}
+	var definition Control
+	found := false
+	for _, catalog := range catalogs {
+		if catalog == nil {
+			continue
+		}
+		if definition, found = findControl(catalog, control.EntryId); found {
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	var covered []string
+	for _, mapping := range definition.ThreatMappings {
+		for _, entry := range mapping.Entries {
+			risk, ok := findAcceptedRisk(accepted, mapping.ReferenceId, entry.ReferenceId)
+			if !ok {
+				return nil
+			}
+			description := risk.Risk.EntryId
+			if risk.Justification != "" {
+				description += ": " + risk.Justification
+			}
+			covered = append(covered, description)
+		}
+	}
+	return covered
+}
+
+func findAcceptedRisk(accepted []AcceptedRisk, referenceId string, threatId string) (AcceptedRisk, bool) {
+	for _, risk := range accepted {
+		if risk.Risk.EntryId != threatId {
+			continue
+		}
+		if risk.Risk.ReferenceId == "" || referenceId == "" || risk.Risk.ReferenceId == referenceId {
+			return risk, true
+		}
+	}
+	return AcceptedRisk{}, false
+}
+
+// failureEnforcement returns the enforcement method applied to failed controls and its action.
+func failureEnforcement(methods []AcceptedMethod) (string, EnforcementAction) {
+	var chosen string
+	action := Warn
+	for _, method := range methods {
+		var candidate EnforcementAction
+		switch MethodType(method.Type) {
+		case "gate":
+			candidate = Block
+		case "autoremediation":
+			candidate = Remediate
+		default:
+			candidate = Warn
+		}
+		if candidate > action || chosen == "" {
+			chosen = describeMethod(method)
+			if candidate > action {
+				action = candidate
+			}
+		}
+	}
+	return chosen, action
+}
+
+func describeMethod(method AcceptedMethod) string {
+	if method.Description == "" {
+		return method.Type + " method"
+	}
+	return fmt.Sprintf("%s method (%s)", method.Type, method.Description)
+}
+
+// enforcementActive reports whether now falls within timeline, and describes the timeline when it does not.
+func enforcementActive(timeline ImplementationDetails, now time.Time) (bool, string, error) {
+	if timeline.Start != "" {
+		start, err := time.Parse(time.RFC3339, string(timeline.Start))
+		if err != nil {
+			return false, "", fmt.Errorf("invalid enforcement timeline start: %w", err)
+		}
+		if now.Before(start) {
+			return false, fmt.Sprintf("enforcement starts at %s", timeline.Start), nil
+		}
+	}
+	if timeline.End != "" {
+		end, err := time.Parse(time.RFC3339, string(timeline.End))
+		if err != nil {
+			return false, "", fmt.Errorf("invalid enforcement timeline end: %w", err)
+		}
+		if now.After(end) {
+			return false, fmt.Sprintf("enforcement ended at %s", timeline.End), nil
+		}
+	}
+	return true, "", nil
+}
@@ -0,0 +1,199 @@
+package gemara
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadEnforcementFixtures(t *testing.T) (*EvaluationLog, *Policy) {
+	t.Helper()
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+	policy := &Policy{}
+	require.NoError(t, policy.LoadFile("file://test-data/good-osps-policy.yaml"))
+	return log, policy
+}
+
+func TestEnforce(t *testing.T) {
+	log, policy := loadEnforcementFixtures(t)
+
+	report, err := Enforce(log, policy, WithEnforcementTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, policy.Metadata.Id, report.Policy)
+	assert.Equal(t, Datetime("2025-07-01T00:00:00Z"), report.Decided)
+	assert.Equal(t, Block, report.Action)
+	assert.Equal(t, policy.Adherence.NonCompliance, report.NonCompliance)
+	require.Len(t, report.Decisions, len(log.Evaluations))
+
+	actions := make(map[string]EnforcementAction)
+	for _, decision := range report.Decisions {
+		actions[decision.Control.EntryId] = decision.Action
+	}
+	assert.Equal(t, map[string]EnforcementAction{
+		"OSPS-AC-01": Allow,
+		"OSPS-AC-03": Block,
+		"OSPS-BR-01": Warn,
+	}, actions)
+
+	decision, ok := report.Decision("OSPS-AC-03")
+	require.True(t, ok)
+	assert.Equal(t, Failed, decision.Result)
+	assert.Contains(t, decision.Rationale, "Branch protection rule does not prevent deletions")
+	assert.Contains(t, decision.Rationale, "gate method")
+}
+
+func TestEnforce_Timeline(t *testing.T) {
+	log, policy := loadEnforcementFixtures(t)
+
+	report, err := Enforce(log, policy, WithEnforcementTime(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, Warn, report.Action)
+	decision, _ := report.Decision("OSPS-AC-03")
+	assert.Equal(t, Warn, decision.Action)
+	assert.Contains(t, decision.Rationale, "enforcement starts at 2025-06-01T00:00:00Z")
+
+	policy.ImplementationPlan.EnforcementTimeline.End = "2025-12-31T00:00:00Z"
+	report, err = Enforce(log, policy, WithEnforcementTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	decision, _ = report.Decision("OSPS-AC-03")
+	assert.Equal(t, Warn, decision.Action)
+	assert.Contains(t, decision.Rationale, "enforcement ended at 2025-12-31T00:00:00Z")
+
+	policy.ImplementationPlan.EnforcementTimeline.Start = "June 2025"
+	_, err = Enforce(log, policy)
+	assert.ErrorContains(t, err, "invalid enforcement timeline start")
+}
+
+func TestEnforce_Methods(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []AcceptedMethod
+		want    EnforcementAction
+	}{
+		{name: "None", want: Warn},
+		{name: "Manual", methods: []AcceptedMethod{{Type: "manual"}}, want: Warn},
+		{name: "Autoremediation", methods: []AcceptedMethod{{Type: "manual"}, {Type: "autoremediation"}}, want: Remediate},
+		{name: "Gate", methods: []AcceptedMethod{{Type: "autoremediation"}, {Type: "gate"}}, want: Block},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &EvaluationLog{Evaluations: []*ControlEvaluation{
+				{Name: "failing", Control: SingleMapping{ReferenceId: "CAT", EntryId: "CTRL-01"}, Result: Failed, Message: "not configured"},
+			}}
+			policy := &Policy{
+				Imports:   Imports{Catalogs: []CatalogImport{{ReferenceId: "CAT"}}},
+				Adherence: Adherence{EnforcementMethods: tt.methods},
+			}
+
+			report, err := Enforce(log, policy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, report.Action)
+			assert.Equal(t, tt.want, report.Decisions[0].Action)
+		})
+	}
+}
+
+func TestEnforce_Inputs(t *testing.T) {
+	log, policy := loadEnforcementFixtures(t)
+
+	_, err := Enforce(nil, policy)
+	assert.EqualError(t, err, "no evaluation log to enforce")
+	_, err = Enforce(log, nil)
+	assert.EqualError(t, err, "no policy to enforce")
+
+	log.Evaluations[2].Control.ReferenceId = "CCC"
+	log.Evaluations[1].Control.ReferenceId = ""
+	_, err = Enforce(log, policy)
+	assert.EqualError(t, err, "evaluation log osps-baseline-evaluation does not match policy example-org-osps-policy: "+
+		"control OSPS-AC-03 has no reference-id; control OSPS-BR-01 references CCC, which the policy does not import")
+}
+
+func TestEnforce_AcceptedRisks(t *testing.T) {
+	log, policy := loadEnforcementFixtures(t)
+	at := WithEnforcementTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	catalog := &Catalog{Controls: []Control{{
+		Id: "OSPS-AC-03",
+		ThreatMappings: []MultiMapping{{
+			ReferenceId: "THREATS",
+			Entries:     []MappingEntry{{ReferenceId: "TH-01"}, {ReferenceId: "TH-02"}},
+		}},
+	}}}
+	policy.Risks.Accepted = []AcceptedRisk{
+		{Risk: SingleMapping{ReferenceId: "THREATS", EntryId: "TH-01"}, Justification: "branches are restored from mirrors"},
+	}
+
+	_, err := Enforce(log, policy, at)
+	assert.ErrorContains(t, err, "no catalogs were provided")
+
+	report, err := Enforce(log, policy, at, WithEnforcementCatalogs(catalog))
+	require.NoError(t, err)
+	decision, _ := report.Decision("OSPS-AC-03")
+	assert.Equal(t, Block, decision.Action, "a control is covered only when all of its threats are accepted")
+
+	policy.Risks.Accepted = append(policy.Risks.Accepted, AcceptedRisk{Risk: SingleMapping{EntryId: "TH-02"}})
+	report, err = Enforce(log, policy, at, WithEnforcementCatalogs(catalog))
+	require.NoError(t, err)
+	decision, _ = report.Decision("OSPS-AC-03")
+	assert.Equal(t, Warn, decision.Action)
+	assert.Contains(t, decision.Rationale, "TH-01: branches are restored from mirrors; TH-02")
+	assert.Equal(t, Warn, report.Action)
+}
+
+func TestEnforce_PlannedLog(t *testing.T) {
+	_, policy := loadEnforcementFixtures(t)
+	source := &Catalog{}
+	require.NoError(t, source.LoadFile("file://test-data/good-osps.yml"))
+	effective, err := policy.ResolveCatalogs(NewCatalogFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep, failingAssessmentStep)
+	registry.Assign("EX-PLAN-AC-01", failingAssessmentStep.Name())
+	registry.Assign("EX-PLAN-AC-03", passingAssessmentStep.Name())
+
+	tests := []struct {
+		name    string
+		catalog *Catalog
+		opts    []PlanOption
+	}{
+		{"imported catalog", source, []PlanOption{WithPolicy(policy)}},
+		{"effective catalog", &effective.Catalog, []PlanOption{WithPolicy(policy), WithControlSources(effective.ControlSources)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, diagnostics := BuildEvaluationLog(planMetadata, tt.catalog, registry, tt.opts...)
+			require.False(t, diagnostics.HasErrors(), diagnostics)
+			log.Evaluate(nil, []string{"Maturity Level 1"})
+
+			report, err := Enforce(log, policy, WithEnforcementTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+			require.NoError(t, err)
+			assert.Equal(t, log.Metadata.Id, report.EvaluationLog)
+			assert.Equal(t, Block, report.Action)
+			ac01, ok := report.Decision("OSPS-AC-01")
+			require.True(t, ok)
+			assert.Equal(t, Block, ac01.Action)
+			ac03, ok := report.Decision("OSPS-AC-03")
+			require.True(t, ok)
+			assert.Equal(t, Allow, ac03.Action)
+		})
+	}
+}
+
+func TestEnforcementAction_Marshal(t *testing.T) {
+	data, err := json.Marshal(Remediate)
+	require.NoError(t, err)
+	assert.Equal(t, `"Remediate"`, string(data))
+
+	var action EnforcementAction
+	require.NoError(t, json.Unmarshal([]byte(`"Block"`), &action))
+	assert.Equal(t, Block, action)
+	assert.Error(t, json.Unmarshal([]byte(`"Deny"`), &action))
+
+	action, err = ParseEnforcementAction("warn")
+	require.NoError(t, err)
+	assert.Equal(t, Warn, action)
+}
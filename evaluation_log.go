@@ -0,0 +1,70 @@
+package gemara
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// WithConcurrency is a RunOption that limits how many control evaluations
+// EvaluationLog.EvaluateContext runs at the same time. It defaults to GOMAXPROCS.
+func WithConcurrency(workers int) RunOption {
+	return func(o *runOptions) {
+		o.concurrency = workers
+	}
+}
+
+// Evaluate runs every control evaluation in the log against targetData, as described by EvaluateContext.
+func (e *EvaluationLog) Evaluate(targetData interface{}, userApplicability []string) {
+	e.EvaluateContext(context.Background(), targetData, userApplicability)
+}
+
+// EvaluateContext runs the control evaluations in the log concurrently, each with
+// ControlEvaluation.EvaluateContext, so the assessments of a control still run in order and halt
+// on the first failure. At most the number of workers set with WithConcurrency run at a time.
+// Results are recorded on each ControlEvaluation, so Evaluations keeps its order regardless of
+// which evaluation finishes first.
+//
+// Steps receive the same targetData from several goroutines and must not modify it.
+// Each AssessmentLog must belong to a single ControlEvaluation.
+func (e *EvaluationLog) EvaluateContext(ctx context.Context, targetData interface{}, userApplicability []string, opts ...RunOption) {
+	e.evaluateEach(opts, func(evaluation *ControlEvaluation) {
+		evaluation.EvaluateContext(ctx, targetData, userApplicability, opts...)
+	})
+}
+
+// EvaluateMatching runs the control evaluations in the log concurrently like EvaluateContext,
+// selecting assessments with ControlEvaluation.EvaluateMatching.
+func (e *EvaluationLog) EvaluateMatching(ctx context.Context, targetData interface{}, matcher ApplicabilityMatcher, opts ...RunOption) {
+	e.evaluateEach(opts, func(evaluation *ControlEvaluation) {
+		evaluation.EvaluateMatching(ctx, targetData, matcher, opts...)
+	})
+}
+
+// evaluateEach calls evaluate for every control evaluation, running at most the number of
+// workers set with WithConcurrency at a time.
+func (e *EvaluationLog) evaluateEach(opts []RunOption, evaluate func(evaluation *ControlEvaluation)) {
+	options := newRunOptions(opts)
+	workers := options.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, evaluation := range e.Evaluations {
+		if evaluation == nil {
+			continue
+		}
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(evaluation *ControlEvaluation) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			evaluate(evaluation)
+		}(evaluation)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package gemara
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluationLog_EvaluateContext(t *testing.T) {
+	var running, maxRunning int32
+	trackingStep := func(interface{}) (Result, string, ConfidenceLevel) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			seen := atomic.LoadInt32(&maxRunning)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return Passed, "tracked", High
+	}
+
+	log := &EvaluationLog{}
+	for i := 0; i < 20; i++ {
+		evaluation := &ControlEvaluation{Name: fmt.Sprintf("control-%02d", i)}
+		evaluation.AddAssessment("requirement", "description", testingApplicability, []AssessmentStep{trackingStep, passingAssessmentStep})
+		if i%5 == 0 {
+			evaluation.AddAssessment("requirement", "description", testingApplicability, []AssessmentStep{failingAssessmentStep, passingAssessmentStep})
+		}
+		log.Evaluations = append(log.Evaluations, evaluation)
+	}
+
+	log.EvaluateContext(context.Background(), nil, testingApplicability, WithConcurrency(4))
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(4))
+	for i, evaluation := range log.Evaluations {
+		assert.Equal(t, fmt.Sprintf("control-%02d", i), evaluation.Name)
+		assert.Equal(t, int64(2), evaluation.AssessmentLogs[0].StepsExecuted)
+		if i%5 == 0 {
+			assert.Equal(t, Failed, evaluation.Result)
+			// Steps within an assessment still halt on the first failure.
+			assert.Equal(t, int64(1), evaluation.AssessmentLogs[1].StepsExecuted)
+		} else {
+			assert.Equal(t, Passed, evaluation.Result)
+		}
+	}
+}
+
+func TestEvaluationLog_Evaluate(t *testing.T) {
+	log := &EvaluationLog{Evaluations: []*ControlEvaluation{
+		{Name: "passing", AssessmentLogs: []*AssessmentLog{passingAssessmentPtr()}},
+		nil,
+		{Name: "empty"},
+	}}
+	log.Evaluate(nil, testingApplicability)
+	assert.Equal(t, Passed, log.Evaluations[0].Result)
+	assert.Equal(t, NeedsReview, log.Evaluations[2].Result)
+}
@@ -0,0 +1,236 @@
+package gemara
+
+import "fmt"
+
+type planOpts struct {
+	policy  *Policy
+	values  ParameterValues
+	sources map[string]SingleMapping
+}
+
+// PlanOption defines an option to tune the behavior of BuildEvaluationLog.
+type PlanOption func(opts *planOpts)
+
+// WithPolicy is a PlanOption that limits the evaluation to the assessment plans of policy.
+// Each plan in Adherence.AssessmentPlans becomes an assessment of its RequirementId.
+func WithPolicy(policy *Policy) PlanOption {
+	return func(opts *planOpts) {
+		opts.policy = policy
+	}
+}
+
+// WithParameterValues is a PlanOption that supplies parameter values for the assessment plans of
+// the policy set with WithPolicy, overriding the values set in the policy itself.
+func WithParameterValues(values ParameterValues) PlanOption {
+	return func(opts *planOpts) {
+		opts.values = values
+	}
+}
+
+// WithControlSources is a PlanOption that records, for every control whose id is in sources, the
+// reference id of its source instead of the id of the catalog passed to BuildEvaluationLog. Pass
+// the ControlSources of the EffectiveCatalog returned by Policy.ResolveCatalogs so that the log
+// references the catalogs the policy imports, as Enforce requires.
+func WithControlSources(sources map[string]SingleMapping) PlanOption {
+	return func(opts *planOpts) {
+		opts.sources = sources
+	}
+}
+
+// BuildEvaluationLog generates the ControlEvaluation and AssessmentLog tree for catalog, ready
+// to be run with EvaluationLog.Evaluate. Each assessment takes its description, applicability
+// and recommendation from the assessment requirement it evaluates, and its steps from registry.
+//
+// The log is described by metadata, which must identify the log and its author as the schema
+// requires; missing fields are reported as errors. Mapping references to the catalog and the
+// policy are added to it unless it already has references with the same ids. Once evaluated,
+// which sets the start time of every assessment, the log conforms to the EvaluationLog schema.
+//
+// Without a policy, every assessment requirement in the catalog is assessed with the steps
+// assigned to its id. With WithPolicy, every assessment plan of the policy is assessed instead,
+// using the steps assigned to the plan id, or to its requirement id when the plan has none, and
+// the plan is recorded in AssessmentLog.Plan with its parameters bound as by AssessmentLog.BindParameters.
+// Parameters that cannot be bound are reported as errors. To evaluate a policy's tailored requirements, pass
+// the Catalog of the EffectiveCatalog returned by Policy.ResolveCatalogs along with WithControlSources:
+//
+//	effective, err := policy.ResolveCatalogs(gemara.FetchCatalogFromURL)
+//	...
+//	log, diagnostics := gemara.BuildEvaluationLog(metadata, &effective.Catalog, registry,
+//		gemara.WithPolicy(policy), gemara.WithControlSources(effective.ControlSources))
+//
+// Requirements and plans without any assigned steps are left out of the log and reported as
+// warnings; assigned steps that are not registered are reported as errors.
+func BuildEvaluationLog(metadata Metadata, catalog *Catalog, registry *StepRegistry, opts ...PlanOption) (*EvaluationLog, Diagnostics) {
+	options := &planOpts{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	b := &planBuilder{
+		metadata: metadata,
+		catalog:  catalog,
+		registry: registry,
+		policy:   options.policy,
+		values:   options.values,
+		sources:  options.sources,
+	}
+	return b.build()
+}
+
+type planBuilder struct {
+	metadata    Metadata
+	catalog     *Catalog
+	registry    *StepRegistry
+	policy      *Policy
+	values      ParameterValues
+	sources     map[string]SingleMapping
+	diagnostics Diagnostics
+}
+
+func (b *planBuilder) report(code DiagnosticCode, severity Severity, path string, format string, args ...interface{}) {
+	b.diagnostics = append(b.diagnostics, Diagnostic{
+		Code:     code,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (b *planBuilder) build() (*EvaluationLog, Diagnostics) {
+	log := &EvaluationLog{Metadata: b.metadata}
+	b.checkMetadata()
+	// Copy the references so that the caller's metadata is left untouched.
+	log.Metadata.MappingReferences = append([]MappingReference(nil), b.metadata.MappingReferences...)
+	addMappingReference(&log.Metadata, MappingReference{
+		Id:      b.catalog.Metadata.Id,
+		Title:   b.catalog.Title,
+		Version: b.catalog.Metadata.Version,
+	})
+	if b.policy != nil {
+		addMappingReference(&log.Metadata, MappingReference{
+			Id:      b.policy.Metadata.Id,
+			Title:   b.policy.Title,
+			Version: b.policy.Metadata.Version,
+		})
+		b.checkPlans()
+	}
+
+	for i, control := range b.catalog.Controls {
+		referenceId := b.referenceId(control.Id)
+		evaluation := &ControlEvaluation{
+			Name:    control.Id,
+			Result:  NotRun,
+			Control: SingleMapping{ReferenceId: referenceId, EntryId: control.Id},
+		}
+		for j, requirement := range control.AssessmentRequirements {
+			path := fmt.Sprintf("controls[%d].assessment-requirements[%d]", i, j)
+			if b.policy == nil {
+				if assessment := b.assessment(path, referenceId, requirement, nil, requirement.Id); assessment != nil {
+					evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, assessment)
+				}
+				continue
+			}
+			for k, plan := range b.policy.Adherence.AssessmentPlans {
+				if plan.RequirementId != requirement.Id {
+					continue
+				}
+				path := fmt.Sprintf("adherence.assessment-plans[%d]", k)
+				if assessment := b.assessment(path, referenceId, requirement, &plan, plan.Id, requirement.Id); assessment != nil {
+					evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, assessment)
+				}
+			}
+		}
+		if len(evaluation.AssessmentLogs) > 0 {
+			log.Evaluations = append(log.Evaluations, evaluation)
+			b.addSourceReference(&log.Metadata, referenceId)
+		}
+	}
+	return log, b.diagnostics
+}
+
+// referenceId returns the reference id recorded for the control with id controlId.
+func (b *planBuilder) referenceId(controlId string) string {
+	if source, ok := b.sources[controlId]; ok && source.ReferenceId != "" {
+		return source.ReferenceId
+	}
+	return b.catalog.Metadata.Id
+}
+
+// addSourceReference adds the mapping reference of a control source to metadata, taking its
+// details from the catalog's mapping references when it has one with that id.
+func (b *planBuilder) addSourceReference(metadata *Metadata, referenceId string) {
+	reference, ok := findMappingReference(b.catalog.Metadata, referenceId)
+	if !ok {
+		reference = MappingReference{Id: referenceId}
+	}
+	addMappingReference(metadata, reference)
+}
+
+// checkMetadata reports the metadata fields the schema requires that are missing.
+func (b *planBuilder) checkMetadata() {
+	required := []struct {
+		path  string
+		value string
+	}{
+		{"metadata.id", b.metadata.Id},
+		{"metadata.description", b.metadata.Description},
+		{"metadata.author.id", b.metadata.Author.Id},
+		{"metadata.author.name", b.metadata.Author.Name},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			b.report(CodeMissingField, SeverityError, field.path, "%s is required for the evaluation log", field.path)
+		}
+	}
+}
+
+func addMappingReference(metadata *Metadata, reference MappingReference) {
+	if _, ok := findMappingReference(*metadata, reference.Id); !ok {
+		metadata.MappingReferences = append(metadata.MappingReferences, reference)
+	}
+}
+
+// checkPlans reports assessment plans whose requirement is not in the catalog.
+func (b *planBuilder) checkPlans() {
+	for k, plan := range b.policy.Adherence.AssessmentPlans {
+		if !hasRequirement(b.catalog.Controls, plan.RequirementId) {
+			b.report(CodeUnknownRequirement, SeverityError, fmt.Sprintf("adherence.assessment-plans[%d].requirement-id", k),
+				"assessment plan %q references requirement %q, which is not in catalog %q", plan.Id, plan.RequirementId, b.catalog.Metadata.Id)
+		}
+	}
+}
+
+// assessment creates the AssessmentLog for requirement using the steps assigned to the first of
+// ids that has any, or returns nil after reporting why it cannot be run. The requirement is
+// recorded under referenceId.
+func (b *planBuilder) assessment(path string, referenceId string, requirement AssessmentRequirement, plan *AssessmentPlan, ids ...string) *AssessmentLog {
+	var steps StepList
+	for _, id := range ids {
+		var err error
+		steps, err = b.registry.StepsFor(id)
+		if err != nil {
+			b.report(CodeUnregisteredStep, SeverityError, path, "%v", err)
+			return nil
+		}
+		if len(steps) > 0 {
+			break
+		}
+	}
+	if len(steps) == 0 {
+		b.report(CodeMissingSteps, SeverityWarning, path, "no steps are registered for %s", ids[0])
+		return nil
+	}
+
+	assessment, err := newAssessment(requirement.Id, requirement.Text, requirement.Applicability, steps)
+	if err != nil {
+		b.report(CodeMissingField, SeverityWarning, path, "assessment of %s cannot run: %v", requirement.Id, err)
+	}
+	assessment.Requirement.ReferenceId = referenceId
+	assessment.Recommendation = requirement.Recommendation
+	if plan != nil {
+		assessment.Plan = &SingleMapping{ReferenceId: b.policy.Metadata.Id, EntryId: plan.Id}
+		if err := assessment.BindParameters(*plan, b.values[plan.Id]); err != nil {
+			b.report(CodeInvalidParameter, SeverityError, path+".parameters", "%v", err)
+		}
+	}
+	return assessment
+}
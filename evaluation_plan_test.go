@@ -0,0 +1,160 @@
+package gemara
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var planMetadata = Metadata{
+	Id:          "example-evaluation",
+	Description: "Evaluation of the example repository",
+	Author:      Actor{Id: "pvtr", Name: "pvtr-github-repo", Type: Software},
+}
+
+func loadPlanFixtures(t *testing.T) (*Catalog, *Policy) {
+	t.Helper()
+	catalog := &Catalog{}
+	require.NoError(t, catalog.LoadFile("file://test-data/good-osps.yml"))
+	policy := &Policy{}
+	require.NoError(t, policy.LoadFile("file://test-data/good-osps-policy.yaml"))
+	return catalog, policy
+}
+
+func TestBuildEvaluationLog_Catalog(t *testing.T) {
+	catalog, _ := loadPlanFixtures(t)
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep)
+	registry.Assign("OSPS-AC-01.01", AssessmentStep(passingAssessmentStep).String())
+	registry.Assign("OSPS-AC-03.02", "example.missingStep")
+
+	log, diagnostics := BuildEvaluationLog(planMetadata, catalog, registry)
+	require.Len(t, log.Evaluations, 1)
+	evaluation := log.Evaluations[0]
+	assert.Equal(t, "OSPS-AC-01", evaluation.Name)
+	assert.Equal(t, SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01"}, evaluation.Control)
+
+	require.Len(t, evaluation.AssessmentLogs, 1)
+	assessment := evaluation.AssessmentLogs[0]
+	requirement := catalog.Controls[0].AssessmentRequirements[0]
+	assert.Equal(t, SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01.01"}, assessment.Requirement)
+	assert.Equal(t, requirement.Text, assessment.Description)
+	assert.Equal(t, requirement.Applicability, assessment.Applicability)
+	assert.Equal(t, requirement.Recommendation, assessment.Recommendation)
+	assert.Nil(t, assessment.Plan)
+
+	assert.True(t, diagnostics.HasErrors())
+	var unregistered, missing int
+	for _, diagnostic := range diagnostics {
+		switch diagnostic.Code {
+		case CodeUnregisteredStep:
+			unregistered++
+			assert.Contains(t, diagnostic.Message, "example.missingStep")
+		case CodeMissingSteps:
+			missing++
+		}
+	}
+	assert.Equal(t, 1, unregistered)
+	assert.Greater(t, missing, 1)
+
+	log.Evaluate(nil, requirement.Applicability)
+	assert.Equal(t, Passed, log.Evaluations[0].Result)
+	assert.Empty(t, log.Validate(WithCatalogs(catalog)).Err())
+}
+
+func TestBuildEvaluationLog_Policy(t *testing.T) {
+	catalog, policy := loadPlanFixtures(t)
+	policy.Adherence.AssessmentPlans = append(policy.Adherence.AssessmentPlans, AssessmentPlan{Id: "EX-PLAN-XX", RequirementId: "OSPS-XX-01.01"})
+
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep, needsReviewAssessmentStep)
+	registry.Assign("OSPS-AC-01.01", AssessmentStep(passingAssessmentStep).String())
+	registry.Assign("EX-PLAN-AC-03", AssessmentStep(needsReviewAssessmentStep).String())
+	registry.Assign("OSPS-AC-03.02", AssessmentStep(passingAssessmentStep).String())
+
+	log, diagnostics := BuildEvaluationLog(planMetadata, catalog, registry, WithPolicy(policy))
+	require.Len(t, log.Evaluations, 2, "only requirements with assessment plans are evaluated")
+	assert.Equal(t, []string{"OSPS-B", "example-org-osps-policy"}, []string{log.Metadata.MappingReferences[0].Id, log.Metadata.MappingReferences[1].Id})
+
+	first := log.Evaluations[0].AssessmentLogs[0]
+	assert.Equal(t, &SingleMapping{ReferenceId: "example-org-osps-policy", EntryId: "EX-PLAN-AC-01"}, first.Plan)
+	assert.Equal(t, AssessmentStep(passingAssessmentStep).String(), first.Steps[0].Name())
+	assert.Nil(t, first.Parameters, "plans without parameters bind none")
+
+	second := log.Evaluations[1]
+	assert.Equal(t, "OSPS-AC-03", second.Name)
+	require.Len(t, second.AssessmentLogs, 1)
+	assert.Equal(t, "OSPS-AC-03.01", second.AssessmentLogs[0].Requirement.EntryId)
+	assert.Equal(t, AssessmentStep(needsReviewAssessmentStep).String(), second.AssessmentLogs[0].Steps[0].Name(), "plan steps take precedence")
+	assert.Equal(t, map[string]string{"minimum-reviewers": "2"}, second.AssessmentLogs[0].Parameters)
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, CodeUnknownRequirement, diagnostics[0].Code)
+	assert.Equal(t, "adherence.assessment-plans[2].requirement-id", diagnostics[0].Path)
+}
+
+func TestBuildEvaluationLog_ResolvedPolicy(t *testing.T) {
+	_, policy := loadPlanFixtures(t)
+	effective, err := policy.ResolveCatalogs(NewCatalogFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep, failingAssessmentStep)
+	registry.Assign("EX-PLAN-AC-01", passingAssessmentStep.Name())
+	registry.Assign("EX-PLAN-AC-03", failingAssessmentStep.Name())
+
+	log, diagnostics := BuildEvaluationLog(planMetadata, &effective.Catalog, registry,
+		WithPolicy(policy), WithControlSources(effective.ControlSources))
+	assert.False(t, diagnostics.HasErrors(), diagnostics)
+	require.Len(t, log.Evaluations, 2)
+	assert.Equal(t, SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01"}, log.Evaluations[0].Control)
+	assert.Equal(t, SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01.01"}, log.Evaluations[0].AssessmentLogs[0].Requirement)
+	reference, ok := findMappingReference(log.Metadata, "OSPS-B")
+	require.True(t, ok, "the log references the imported catalog")
+	assert.Equal(t, "Open Source Project Security Baseline", reference.Title)
+
+	log.Evaluate(nil, []string{"Maturity Level 1"})
+	assert.Equal(t, Passed, log.Evaluations[0].Result)
+	assert.Equal(t, Failed, log.Evaluations[1].Result)
+
+	report, err := Enforce(log, policy, WithEnforcementTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, Block, report.Action)
+	decision, ok := report.Decision("OSPS-AC-03")
+	require.True(t, ok)
+	assert.Equal(t, Block, decision.Action)
+
+	unsourced, _ := BuildEvaluationLog(planMetadata, &effective.Catalog, registry, WithPolicy(policy))
+	_, err = Enforce(unsourced, policy)
+	assert.ErrorContains(t, err, "control OSPS-AC-01 references example-org-osps-policy, which the policy does not import")
+}
+
+func TestBuildEvaluationLog_Schema(t *testing.T) {
+	catalog, policy := loadPlanFixtures(t)
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep)
+	registry.Assign("OSPS-AC-01.01", passingAssessmentStep.Name())
+	registry.Assign("OSPS-AC-03.01", passingAssessmentStep.Name())
+
+	log, diagnostics := BuildEvaluationLog(planMetadata, catalog, registry, WithPolicy(policy))
+	assert.False(t, diagnostics.HasErrors(), diagnostics)
+	assert.Empty(t, planMetadata.MappingReferences, "the metadata passed in is left untouched")
+
+	log.Evaluate(nil, catalog.Controls[0].AssessmentRequirements[0].Applicability)
+	data, err := Marshal(log, FormatYAML)
+	require.NoError(t, err)
+	violations, err := ValidateAgainstSchema(KindEvaluationLog, data)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	_, diagnostics = BuildEvaluationLog(Metadata{Id: "example-evaluation"}, catalog, registry)
+	var missing []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code == CodeMissingField {
+			missing = append(missing, diagnostic.Path)
+		}
+	}
+	assert.Equal(t, []string{"metadata.description", "metadata.author.id", "metadata.author.name"}, missing)
+}
@@ -0,0 +1,111 @@
+package gemara
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// InlineEvidence returns evidence that carries content, such as command output, with its digest.
+func InlineEvidence(description string, content string) Evidence {
+	return Evidence{
+		Description: description,
+		Content:     content,
+		Digest:      sha256Digest([]byte(content)),
+		Collected:   Datetime(time.Now().Format(time.RFC3339)),
+	}
+}
+
+// FileEvidence returns evidence that references the file at filePath by its absolute file URI,
+// with the digest of its current content so that later changes to the file can be detected.
+func FileEvidence(description string, filePath string) (Evidence, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Evidence{}, fmt.Errorf("error reading evidence: %w", err)
+	}
+	absolute, err := filepath.Abs(filePath)
+	if err != nil {
+		return Evidence{}, fmt.Errorf("error reading evidence: %w", err)
+	}
+	return Evidence{
+		Description: description,
+		Uri:         "file://" + filepath.ToSlash(absolute),
+		Digest:      sha256Digest(data),
+		Collected:   Datetime(time.Now().Format(time.RFC3339)),
+	}, nil
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// WithEvidenceCollector is a RunOption that sets the collector of evidence returned without one.
+func WithEvidenceCollector(collector Actor) RunOption {
+	return func(o *runOptions) {
+		o.collector = &collector
+	}
+}
+
+// AddEvidence attaches evidence to the assessment, setting the collection time when it is missing.
+func (a *AssessmentLog) AddEvidence(evidence ...Evidence) {
+	now := Datetime(time.Now().Format(time.RFC3339))
+	for _, item := range evidence {
+		if item.Collected == "" {
+			item.Collected = now
+		}
+		a.Evidence = append(a.Evidence, item)
+	}
+}
+
+// recordEvidence attaches the evidence returned by step, filling in the collection time, the
+// step and the collector when they are missing.
+func (a *AssessmentLog) recordEvidence(step string, collector *Actor, evidence []Evidence) {
+	now := Datetime(time.Now().Format(time.RFC3339))
+	for _, item := range evidence {
+		if item.Collected == "" {
+			item.Collected = now
+		}
+		if item.Step == "" {
+			item.Step = step
+		}
+		if item.Collector == nil {
+			item.Collector = collector
+		}
+		a.Evidence = append(a.Evidence, item)
+	}
+}
+
+// CollectEvidence returns a Step that runs step and adds the evidence returned by collect for
+// the same input to its result. It lets steps that cannot return evidence themselves, such as
+// an AssessmentStep, justify their results:
+//
+//	step := CollectEvidence(AssessmentStep(checkMFA), func(input StepInput) []Evidence {
+//		return []Evidence{InlineEvidence("MFA settings", settingsOf(input.Payload))}
+//	})
+//
+// The step keeps the name of the wrapped step. Evidence is not collected when the step is
+// abandoned because it timed out or was cancelled.
+func CollectEvidence(step Step, collect func(input StepInput) []Evidence) Step {
+	return evidenceStep{step: step, collect: collect}
+}
+
+type evidenceStep struct {
+	step    Step
+	collect func(input StepInput) []Evidence
+}
+
+func (s evidenceStep) Name() string { return stepName(s.step) }
+
+func (s evidenceStep) Run(ctx context.Context, input StepInput) StepResult {
+	result := s.step.Run(ctx, input)
+	if ctx.Err() != nil {
+		return result
+	}
+	result.Evidence = append(result.Evidence, s.collect(input)...)
+	return result
+}
@@ -0,0 +1,99 @@
+package gemara
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineEvidence(t *testing.T) {
+	evidence := InlineEvidence("settings", "two_factor_requirement_enabled: true")
+	assert.Equal(t, "sha256:6e632039f6bc3b57e3ca8b5a8f4c7a69f8985b057921fb74eb169ad67e880cc5", evidence.Digest)
+	assert.NotEmpty(t, evidence.Collected)
+}
+
+func TestFileEvidence(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "settings.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("two_factor_requirement_enabled: true"), 0600))
+
+	evidence, err := FileEvidence("settings", filePath)
+	require.NoError(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(filePath), evidence.Uri)
+	assert.Equal(t, "sha256:6e632039f6bc3b57e3ca8b5a8f4c7a69f8985b057921fb74eb169ad67e880cc5", evidence.Digest)
+	assert.Empty(t, evidence.Content)
+
+	_, err = FileEvidence("settings", filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "error reading evidence")
+}
+
+func TestStepResult_Evidence(t *testing.T) {
+	collectSettings := StepFunc(func(ctx context.Context, _ StepInput) StepResult {
+		return StepResult{
+			Result:          Passed,
+			Message:         "MFA required",
+			ConfidenceLevel: High,
+			Evidence:        []Evidence{InlineEvidence("settings", "mfa: required")},
+		}
+	})
+	collector := Actor{Id: "pvtr", Name: "pvtr-github-repo", Type: Software}
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{collectSettings, passingAssessmentStep})
+	require.NoError(t, err)
+
+	assert.Equal(t, Passed, assessment.RunContext(context.Background(), nil, WithEvidenceCollector(collector)))
+	require.Len(t, assessment.Evidence, 1)
+	assert.Equal(t, collectSettings.Name(), assessment.Evidence[0].Step)
+	assert.Equal(t, &collector, assessment.Evidence[0].Collector)
+
+	assessment.Run(nil)
+	assert.Len(t, assessment.Evidence, 1, "evidence from a previous run is replaced")
+}
+
+func TestCollectEvidence(t *testing.T) {
+	step := CollectEvidence(passingAssessmentStep, func(input StepInput) []Evidence {
+		return []Evidence{InlineEvidence("payload", input.Payload.(string))}
+	})
+	assert.Equal(t, passingAssessmentStep.Name(), step.Name())
+
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{step})
+	require.NoError(t, err)
+	assert.Equal(t, Passed, assessment.Run("mfa: required"))
+	require.Len(t, assessment.Evidence, 1)
+	assert.Equal(t, "mfa: required", assessment.Evidence[0].Content)
+	assert.Equal(t, passingAssessmentStep.Name(), assessment.Evidence[0].Step)
+}
+
+func TestStepResult_Evidence_AbandonedStep(t *testing.T) {
+	returned := make(chan struct{})
+	slowStep := StepFunc(func(ctx context.Context, _ StepInput) StepResult {
+		defer close(returned)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return StepResult{Result: Passed, ConfidenceLevel: High, Evidence: []Evidence{InlineEvidence("late", "too late")}}
+	})
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{slowStep})
+	require.NoError(t, err)
+
+	assert.Equal(t, Unknown, assessment.RunContext(context.Background(), nil, WithStepTimeout(10*time.Millisecond)))
+	<-returned
+	assert.Empty(t, assessment.Evidence)
+}
+
+func TestAssessmentLog_Evidence_RoundTrip(t *testing.T) {
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+	evidence := log.Evaluations[0].AssessmentLogs[0].Evidence
+	require.Len(t, evidence, 1)
+	require.NotNil(t, evidence[0].Collector)
+	assert.Equal(t, "pvtr", evidence[0].Collector.Id)
+
+	data, err := Marshal(log, FormatJSON)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"digest": "sha256:6e632039`), "evidence is serialized")
+	assert.Equal(t, 1, strings.Count(string(data), `"collector"`), "evidence without a collector omits it")
+}
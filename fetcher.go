@@ -0,0 +1,285 @@
+package gemara
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Fetcher retrieves the raw content of a document identified by a URI or path.
+// Implementations other than the ones provided here, such as OCI registries or git
+// repositories, can be plugged in anywhere a Fetcher is accepted.
+type Fetcher interface {
+	Fetch(uri string) (io.ReadCloser, error)
+}
+
+// FetcherFunc adapts an ordinary function to the Fetcher interface.
+type FetcherFunc func(uri string) (io.ReadCloser, error)
+
+func (f FetcherFunc) Fetch(uri string) (io.ReadCloser, error) { return f(uri) }
+
+// DefaultFetcher reads file URIs and plain paths from disk and https URIs with the default HTTPFetcher.
+var DefaultFetcher Fetcher = SchemeFetcher{
+	"":      FileFetcher{},
+	"file":  FileFetcher{},
+	"https": NewHTTPFetcher(),
+}
+
+// SchemeFetcher dispatches to a Fetcher based on the URI scheme.
+// Plain paths without a scheme use the fetcher registered for the empty scheme.
+type SchemeFetcher map[string]Fetcher
+
+func (s SchemeFetcher) Fetch(uri string) (io.ReadCloser, error) {
+	scheme := uriScheme(uri)
+	fetcher, ok := s[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported scheme: %s", scheme)
+	}
+	return fetcher.Fetch(uri)
+}
+
+// FileFetcher reads file URIs and plain paths from disk.
+// Relative paths are resolved against Dir, or the working directory when Dir is empty.
+type FileFetcher struct {
+	Dir string
+}
+
+func (f FileFetcher) Fetch(uri string) (io.ReadCloser, error) {
+	filePath := strings.TrimPrefix(uri, "file://")
+	if f.Dir != "" && !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(f.Dir, filePath)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	return file, nil
+}
+
+// RelativeFetcher returns a Fetcher that resolves relative file URIs and plain paths against dir,
+// typically the directory of the document whose mapping references are fetched, and passes
+// every other URI to fetcher.
+func RelativeFetcher(dir string, fetcher Fetcher) Fetcher {
+	file := FileFetcher{Dir: dir}
+	return FetcherFunc(func(uri string) (io.ReadCloser, error) {
+		switch uriScheme(uri) {
+		case "", "file":
+			return file.Fetch(uri)
+		}
+		return fetcher.Fetch(uri)
+	})
+}
+
+// FSFetcher reads file URIs and plain paths from a file system, such as an embed.FS.
+type FSFetcher struct {
+	FS fs.FS
+}
+
+func (f FSFetcher) Fetch(uri string) (io.ReadCloser, error) {
+	name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(uri, "file://")), "/")
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	return file, nil
+}
+
+// HTTPFetcher retrieves http and https URIs.
+type HTTPFetcher struct {
+	client *http.Client
+	header http.Header
+}
+
+// HTTPOption defines an option to configure an HTTPFetcher.
+type HTTPOption func(f *HTTPFetcher)
+
+// WithHTTPClient is an HTTPOption that replaces the client used for requests.
+// A nil client restores the default client.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(f *HTTPFetcher) {
+		if client == nil {
+			client = newHTTPClient()
+		}
+		f.client = client
+	}
+}
+
+// WithTimeout is an HTTPOption that limits the duration of each request.
+// The client is copied, so a client passed to WithHTTPClient is left unchanged.
+func WithTimeout(timeout time.Duration) HTTPOption {
+	return func(f *HTTPFetcher) {
+		client := newHTTPClient()
+		if f.client != nil {
+			*client = *f.client
+		}
+		client.Timeout = timeout
+		f.client = client
+	}
+}
+
+// WithHeader is an HTTPOption that adds a header, such as Authorization, to every request.
+func WithHeader(key, value string) HTTPOption {
+	return func(f *HTTPFetcher) {
+		f.header.Add(key, value)
+	}
+}
+
+// NewHTTPFetcher returns an HTTPFetcher with a 30 second timeout, adjusted by opts.
+func NewHTTPFetcher(opts ...HTTPOption) *HTTPFetcher {
+	f := &HTTPFetcher{
+		client: newHTTPClient(),
+		header: make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func (f *HTTPFetcher) Fetch(uri string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range f.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch URL; response status: %v", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// ErrNotCached is returned by an offline CacheFetcher for remote documents missing from its cache.
+var ErrNotCached = errors.New("document is not cached")
+
+// CacheFetcher serves http and https URIs from a cache directory that mirrors their host and path,
+// so https://example.com/catalogs/osps.yaml is stored at Dir/example.com/catalogs/osps.yaml.
+// Documents missing from the cache are retrieved with Fetcher and stored, unless Offline is set.
+// Other URIs and plain paths are passed to Fetcher unchanged.
+type CacheFetcher struct {
+	Dir string
+	// Fetcher retrieves documents that are not cached. DefaultFetcher is used when nil.
+	Fetcher Fetcher
+	// Offline prevents any document missing from the cache from being retrieved.
+	Offline bool
+}
+
+// Path returns the location of uri in the cache, or an empty string if uri is not cacheable.
+// URIs whose host or path could address a file outside Dir, such as a host of ".." or a path
+// containing a backslash, are not cacheable. A port is kept in the directory name, written as
+// host_port.
+func (c CacheFetcher) Path(uri string) string {
+	scheme := uriScheme(uri)
+	if scheme != "http" && scheme != "https" {
+		return ""
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	host := parsed.Hostname()
+	if host == "" || host == "." || host == ".." || strings.ContainsAny(host, `/\:`) {
+		return ""
+	}
+	if port := parsed.Port(); port != "" {
+		host += "_" + port
+	}
+	if strings.ContainsAny(parsed.Path, `\:`) || strings.ContainsRune(parsed.Path, 0) {
+		return ""
+	}
+	cleaned := path.Clean("/" + parsed.Path)
+	if cleaned == "/" {
+		return ""
+	}
+	return filepath.Join(c.Dir, host, filepath.FromSlash(cleaned))
+}
+
+func (c CacheFetcher) Fetch(uri string) (io.ReadCloser, error) {
+	fetcher := c.Fetcher
+	if fetcher == nil {
+		fetcher = DefaultFetcher
+	}
+	cachePath := c.Path(uri)
+	if cachePath == "" {
+		return fetcher.Fetch(uri)
+	}
+	if file, err := os.Open(cachePath); err == nil {
+		return file, nil
+	}
+	if c.Offline {
+		return nil, fmt.Errorf("%w: %s", ErrNotCached, uri)
+	}
+
+	reader, err := fetcher.Fetch(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", uri, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0750); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0600); err != nil {
+		return nil, fmt.Errorf("error writing cache: %w", err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// NewCatalogFetcher returns a CatalogFetcher that loads the url of each mapping reference through fetcher.
+func NewCatalogFetcher(fetcher Fetcher) CatalogFetcher {
+	return func(reference MappingReference) (*Catalog, error) {
+		if reference.Url == "" {
+			return nil, fmt.Errorf("mapping reference %q has no url", reference.Id)
+		}
+		catalog := &Catalog{}
+		if err := Decode(FromURI(reference.Url, fetcher), catalog); err != nil {
+			return nil, fmt.Errorf("error loading mapping reference %q: %w", reference.Id, err)
+		}
+		return catalog, nil
+	}
+}
+
+// NewGuidanceFetcher returns a GuidanceFetcher that loads the url of each mapping reference through fetcher.
+func NewGuidanceFetcher(fetcher Fetcher) GuidanceFetcher {
+	return func(reference MappingReference) (*GuidanceDocument, error) {
+		if reference.Url == "" {
+			return nil, fmt.Errorf("mapping reference %q has no url", reference.Id)
+		}
+		doc := &GuidanceDocument{}
+		if err := Decode(FromURI(reference.Url, fetcher), doc); err != nil {
+			return nil, fmt.Errorf("error loading mapping reference %q: %w", reference.Id, err)
+		}
+		return doc, nil
+	}
+}
+
+func uriScheme(uri string) string {
+	if i := strings.Index(uri, "://"); i > 0 {
+		return uri[:i]
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package gemara
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, fetcher Fetcher, uri string) string {
+	t.Helper()
+	reader, err := fetcher.Fetch(uri)
+	require.NoError(t, err)
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFileFetcher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "doc.yaml"), []byte("title: x"), 0600))
+
+	assert.Equal(t, "title: x", readAll(t, FileFetcher{Dir: dir}, "doc.yaml"))
+	assert.Equal(t, "title: x", readAll(t, FileFetcher{}, "file://"+filepath.Join(dir, "doc.yaml")))
+
+	_, err := FileFetcher{}.Fetch("doc.yaml")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFSFetcher(t *testing.T) {
+	fetcher := FSFetcher{FS: fstest.MapFS{"catalogs/doc.yaml": {Data: []byte("title: x")}}}
+	assert.Equal(t, "title: x", readAll(t, fetcher, "catalogs/doc.yaml"))
+	assert.Equal(t, "title: x", readAll(t, fetcher, "file:///catalogs/doc.yaml"))
+
+	_, err := fetcher.Fetch("../doc.yaml")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/slow.yaml":
+			time.Sleep(200 * time.Millisecond)
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("title: " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(WithHTTPClient(server.Client()), WithHeader("Authorization", "Bearer secret"))
+	assert.Equal(t, "title: /doc.yaml", readAll(t, fetcher, server.URL+"/doc.yaml"))
+
+	_, err := NewHTTPFetcher(WithHTTPClient(server.Client())).Fetch(server.URL + "/doc.yaml")
+	assert.ErrorContains(t, err, "response status: 401 Unauthorized")
+
+	_, err = NewHTTPFetcher(WithHTTPClient(server.Client()), WithTimeout(10*time.Millisecond)).Fetch(server.URL + "/slow.yaml")
+	assert.ErrorContains(t, err, "failed to fetch URL")
+
+	assert.NotPanics(t, func() {
+		fetcher := NewHTTPFetcher(WithHTTPClient(nil), WithTimeout(time.Second), WithHeader("Authorization", "Bearer secret"))
+		assert.Equal(t, "title: /doc.yaml", readAll(t, fetcher, server.URL+"/doc.yaml"))
+	})
+}
+
+func TestSchemeFetcher(t *testing.T) {
+	fetcher := SchemeFetcher{"": FSFetcher{FS: fstest.MapFS{"doc.yaml": {Data: []byte("title: x")}}}}
+	assert.Equal(t, "title: x", readAll(t, fetcher, "doc.yaml"))
+
+	_, err := fetcher.Fetch("oci://registry/doc:1")
+	assert.EqualError(t, err, "unsupported scheme: oci")
+}
+
+func TestCacheFetcher(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("title: cached"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	online := CacheFetcher{Dir: dir, Fetcher: NewHTTPFetcher(WithHTTPClient(server.Client()))}
+	uri := server.URL + "/catalogs/doc.yaml"
+	assert.Equal(t, "title: cached", readAll(t, online, uri))
+	assert.Equal(t, "title: cached", readAll(t, online, uri))
+	assert.Equal(t, 1, requests, "the second fetch should be served from the cache")
+	assert.FileExists(t, online.Path(uri))
+	assert.Equal(t, dir, filepath.Dir(filepath.Dir(filepath.Dir(online.Path(uri)))))
+
+	offline := CacheFetcher{Dir: dir, Offline: true}
+	assert.Equal(t, "title: cached", readAll(t, offline, uri))
+	_, err := offline.Fetch(server.URL + "/other.yaml")
+	assert.ErrorIs(t, err, ErrNotCached)
+	assert.Equal(t, 1, requests)
+
+	assert.Empty(t, offline.Path("test-data/good-osps.yml"))
+	assert.Contains(t, readAll(t, offline, "test-data/good-osps.yml"), "OSPS-B")
+}
+
+func TestCacheFetcher_Path(t *testing.T) {
+	dir := filepath.Join("cache", "docs")
+	fetcher := CacheFetcher{Dir: dir}
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"https://example.com/catalogs/osps.yaml", filepath.Join(dir, "example.com", "catalogs", "osps.yaml")},
+		{"https://example.com:8443/osps.yaml", filepath.Join(dir, "example.com_8443", "osps.yaml")},
+		{"https://example.com/../../../etc/passwd", filepath.Join(dir, "example.com", "etc", "passwd")},
+		{"https://example.com/%2e%2e/%2e%2e/osps.yaml", filepath.Join(dir, "example.com", "osps.yaml")},
+		{"https://../osps.yaml", ""},
+		{"https://example.com/..%5c..%5cosps.yaml", ""},
+		{"https://example.com/", ""},
+		{"https:///osps.yaml", ""},
+		{"file:///osps.yaml", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			assert.Equal(t, tt.want, fetcher.Path(tt.uri))
+		})
+	}
+}
+
+func TestLoadFile_UsesDefaultFetcher(t *testing.T) {
+	server := httptest.NewTLSServer(http.FileServer(http.Dir("test-data")))
+	defer server.Close()
+	original := DefaultFetcher
+	t.Cleanup(func() { DefaultFetcher = original })
+	DefaultFetcher = SchemeFetcher{"https": NewHTTPFetcher(WithHTTPClient(server.Client()))}
+
+	catalog := &Catalog{}
+	require.NoError(t, catalog.LoadFile(server.URL+"/good-osps.yml"))
+	assert.Equal(t, "OSPS-B", catalog.Metadata.Id)
+
+	resolved, err := FetchCatalogFromURL(MappingReference{Id: "OSPS-B", Url: server.URL + "/good-osps.yml"})
+	require.NoError(t, err)
+	assert.Equal(t, catalog.Metadata.Id, resolved.Metadata.Id)
+}
+
+func TestNewCatalogFetcher(t *testing.T) {
+	mirror := filepath.Join(t.TempDir(), "baseline.openssf.org")
+	require.NoError(t, os.MkdirAll(mirror, 0750))
+	data, err := os.ReadFile("test-data/good-osps.yml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(mirror, "osps.yml"), data, 0600))
+
+	root := &Catalog{
+		Metadata: Metadata{
+			Id: "ROOT",
+			MappingReferences: []MappingReference{
+				{Id: "OSPS-B", Title: "OSPS Baseline", Version: "1", Url: "https://baseline.openssf.org/osps.yml"},
+			},
+		},
+		ImportedControls: []MultiMapping{{ReferenceId: "OSPS-B", Entries: []MappingEntry{{ReferenceId: "OSPS-AC-01"}}}},
+	}
+	resolved, err := root.Resolve(NewCatalogFetcher(CacheFetcher{Dir: filepath.Dir(mirror), Offline: true}))
+	require.NoError(t, err)
+	require.Len(t, resolved.Catalog.Controls, 1)
+	assert.Equal(t, "OSPS-AC-01", resolved.Catalog.Controls[0].Id)
+
+	_, err = NewCatalogFetcher(DefaultFetcher)(MappingReference{Id: "EMPTY"})
+	assert.EqualError(t, err, `mapping reference "EMPTY" has no url`)
+
+	guidance, err := NewGuidanceFetcher(DefaultFetcher)(MappingReference{Id: "AIGF", Url: "test-data/good-aigf.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "FINOS-AIR", guidance.Metadata.Id)
+}
@@ -0,0 +1,188 @@
+package gemara
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// FormatDocument rewrites a serialized document of any kind into the canonical layout
+// produced by Marshal without otherwise changing its content: fields the schema does not
+// define are kept after the known ones, and YAML comments stay on the fields they were
+// attached to. The result is encoded in format, which is normally the format of data.
+func FormatDocument(data []byte, format Format) ([]byte, error) {
+	kind, err := DetectKind(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := NewDocument(kind)
+	if err != nil {
+		return nil, err
+	}
+	content, err := orderedContent(data, reflect.TypeOf(doc))
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(content, format, WithCommentsFrom(data))
+}
+
+// orderDocument converts doc to an ordered mapping in the canonical field order.
+func orderDocument(doc Document) (yaml.MapSlice, error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding YAML: %w", err)
+	}
+	return orderedContent(data, reflect.TypeOf(doc))
+}
+
+// orderedContent decodes data, a document of type t, into an ordered mapping whose fields
+// are in schema order, with metadata moved to the front.
+func orderedContent(data []byte, t reflect.Type) (yaml.MapSlice, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+	if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		return nil, fmt.Errorf("error decoding document: empty document")
+	}
+	value, err := orderedValue(file.Docs[0].Body, t)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+	content, ok := value.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("error decoding document: expected a mapping")
+	}
+	sort.SliceStable(content, func(i, j int) bool {
+		return content[i].Key == "metadata" && content[j].Key != "metadata"
+	})
+	return content, nil
+}
+
+// orderedValue converts node into plain values, ordering the fields of every mapping by the
+// declaration order of the matching fields of t, which follows the order of the schema the
+// types are generated from. t is nil for values the schema does not describe. Scalars in
+// string fields keep their text, so a version written as 1.10 does not become the number 1.1.
+func orderedValue(node ast.Node, t reflect.Type) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return orderedMapping(n.Values, t)
+	case *ast.MappingValueNode:
+		return orderedMapping([]*ast.MappingValueNode{n}, t)
+	case *ast.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		items := make([]interface{}, len(n.Values))
+		for i, item := range n.Values {
+			value, err := orderedValue(item, elem)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case *ast.AnchorNode:
+		return orderedValue(n.Value, t)
+	case *ast.TagNode:
+		return orderedValue(n.Value, t)
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.InfinityNode, *ast.NanNode:
+		if t != nil && t.Kind() == reflect.String {
+			return n.GetToken().Value, nil
+		}
+	}
+	var value interface{}
+	if err := yaml.NodeToValue(node, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func orderedMapping(values []*ast.MappingValueNode, t reflect.Type) (yaml.MapSlice, error) {
+	fields := make(map[string]reflect.StructField)
+	order := make(map[string]int)
+	if t != nil && t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			fields[name] = t.Field(i)
+			order[name] = i
+		}
+	}
+	mapping := make(yaml.MapSlice, len(values))
+	for i, item := range values {
+		key := item.Key.GetToken().Value
+		var fieldType reflect.Type
+		if field, ok := fields[key]; ok {
+			fieldType = field.Type
+		}
+		value, err := orderedValue(item.Value, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		mapping[i] = yaml.MapItem{Key: key, Value: value}
+	}
+	rank := func(item yaml.MapItem) int {
+		if i, ok := order[item.Key.(string)]; ok {
+			return i
+		}
+		return len(order)
+	}
+	sort.SliceStable(mapping, func(i, j int) bool {
+		return rank(mapping[i]) < rank(mapping[j])
+	})
+	return mapping, nil
+}
+
+// encodeOrderedJSON writes value as compact JSON, keeping the order of the fields of
+// ordered mappings. Other values are encoded with encoding/json.
+func encodeOrderedJSON(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeOrderedJSON(buf, fmt.Sprint(item.Key)); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encodeOrderedJSON(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeOrderedJSON(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	// Encode terminates each value with a newline, which json.Indent would otherwise keep.
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
@@ -0,0 +1,94 @@
+package gemara
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"Guidance", "test-data/good-aigf.yaml"},
+		{"Catalog", "test-data/good-osps.yml"},
+		{"Policy", "test-data/good-osps-policy.yaml"},
+		{"EvaluationLog", "test-data/good-evaluation-log.yaml"},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{FormatYAML, FormatJSON} {
+			t.Run(tt.name+" "+string(format), func(t *testing.T) {
+				data, err := os.ReadFile(tt.path)
+				require.NoError(t, err)
+
+				formatted, err := FormatDocument(data, format)
+				require.NoError(t, err)
+				again, err := FormatDocument(formatted, format)
+				require.NoError(t, err)
+				assert.Equal(t, string(formatted), string(again), "formatting should be idempotent")
+
+				// Formatting must not change the decoded content.
+				original, err := LoadSource(FromPath(tt.path))
+				require.NoError(t, err)
+				reformatted, err := LoadSource(FromReader(bytes.NewReader(formatted), format))
+				require.NoError(t, err)
+				want, err := Marshal(original, format)
+				require.NoError(t, err)
+				got, err := Marshal(reformatted, format)
+				require.NoError(t, err)
+				assert.Equal(t, string(want), string(got))
+			})
+		}
+	}
+}
+
+func TestFormatDocument_Layout(t *testing.T) {
+	data := []byte(`# The catalog title.
+title: Example
+controls:
+  - title: Control
+    # Controls are identified by id.
+    id: EX-01
+    family: F
+    x-owner: security
+    objective: "line one\nline two"
+families:
+- {id: F, title: Family, description: A family}
+metadata:
+  id: EX
+  version: 1.10
+`)
+	want := `metadata:
+  id: EX
+  version: "1.10"
+# The catalog title.
+title: Example
+families:
+  - id: F
+    title: Family
+    description: A family
+controls:
+  - # Controls are identified by id.
+    id: EX-01
+    title: Control
+    objective: |-
+      line one
+      line two
+    family: F
+    x-owner: security
+`
+	formatted, err := FormatDocument(data, FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(formatted))
+
+	formatted, err = FormatDocument(data, FormatJSON)
+	require.NoError(t, err)
+	assert.Contains(t, string(formatted), "{\n  \"metadata\": {\n    \"id\": \"EX\",\n    \"version\": \"1.10\"\n  },\n  \"title\": \"Example\",")
+
+	_, err = FormatDocument([]byte("title: x"), FormatYAML)
+	assert.ErrorContains(t, err, "unable to detect document kind")
+}
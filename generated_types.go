@@ -243,7 +243,7 @@ type AssessmentLog struct {
 	Applicability []string `json:"applicability" yaml:"applicability"`
 
 	// Steps are sequential actions taken as part of the assessment, which may halt the assessment if a failure occurs.
-	Steps []AssessmentStep `json:"steps" yaml:"steps"`
+	Steps StepList `json:"steps" yaml:"steps"`
 
 	// Steps-executed is the number of steps that were executed as part of the assessment.
 	StepsExecuted int64 `json:"steps-executed,omitempty" yaml:"steps-executed,omitempty"`
@@ -259,6 +259,66 @@ type AssessmentLog struct {
 
 	// ConfidenceLevel indicates the evaluator's confidence level in this specific assessment result.
 	ConfidenceLevel ConfidenceLevel `json:"confidence-level,omitempty" yaml:"confidence-level,omitempty"`
+
+	// Step-records trace the outcome of each step that was executed, in the order the steps ran.
+	StepRecords []StepRecord `json:"step-records,omitempty" yaml:"step-records,omitempty"`
+
+	// Evidence justifies the result of the assessment.
+	Evidence []Evidence `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+
+	// Parameters are the values of the assessment plan parameters the steps were run with, keyed by parameter id.
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// StepRecord captures the outcome of a single assessment step execution.
+type StepRecord struct {
+	// Name identifies the step, matching its entry in the steps of the assessment.
+	Name string `json:"name" yaml:"name"`
+
+	// Result is the outcome reported by the step.
+	Result Result `json:"result" yaml:"result"`
+
+	// Message is the context reported by the step.
+	Message string `json:"message" yaml:"message"`
+
+	// ConfidenceLevel is the confidence reported by the step.
+	ConfidenceLevel ConfidenceLevel `json:"confidence-level,omitempty" yaml:"confidence-level,omitempty"`
+
+	// Start is the timestamp when the step began.
+	Start Datetime `json:"start" yaml:"start"`
+
+	// End is the timestamp when the step concluded.
+	End Datetime `json:"end,omitempty" yaml:"end,omitempty"`
+
+	// Duration is the time the step took to run, in Go duration format such as "1.5s".
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+
+	// Stack is the stack trace captured when the step panicked.
+	Stack string `json:"stack,omitempty" yaml:"stack,omitempty"`
+}
+
+// Evidence is an item collected to justify an assessment result.
+type Evidence struct {
+	// Description explains what the evidence shows.
+	Description string `json:"description" yaml:"description"`
+
+	// Content holds the evidence inline, such as a configuration snippet or command output.
+	Content string `json:"content,omitempty" yaml:"content,omitempty"`
+
+	// Uri references evidence kept elsewhere, such as a file or an object in an evidence store.
+	Uri string `json:"uri,omitempty" yaml:"uri,omitempty"`
+
+	// Digest identifies the content of the evidence as algorithm:hex, such as "sha256:9f86d0...".
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// Collected is the timestamp when the evidence was collected.
+	Collected Datetime `json:"collected" yaml:"collected"`
+
+	// Collector identifies the actor that collected the evidence.
+	Collector *Actor `json:"collector,omitempty" yaml:"collector,omitempty"`
+
+	// Step names the assessment step that collected the evidence.
+	Step string `json:"step,omitempty" yaml:"step,omitempty"`
 }
 
 type GuidanceDocument struct {
@@ -550,6 +610,9 @@ type Parameter struct {
 	Description string `json:"description" yaml:"description"`
 
 	AcceptedValues []string `json:"accepted-values,omitempty" yaml:"accepted-values,omitempty"`
+
+	// Value is the value chosen by the organization, which must be one of the accepted values when they are listed.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
 }
 
 type MethodType string
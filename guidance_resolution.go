@@ -0,0 +1,265 @@
+package gemara
+
+import (
+	"fmt"
+)
+
+// GuidanceFetcher retrieves the guidance document described by a mapping reference.
+type GuidanceFetcher func(reference MappingReference) (*GuidanceDocument, error)
+
+// FetchGuidanceFromURL is a GuidanceFetcher that loads the guidance document from the reference's url with DefaultFetcher,
+// as NewGuidanceFetcher(DefaultFetcher) does.
+func FetchGuidanceFromURL(reference MappingReference) (*GuidanceDocument, error) {
+	return NewGuidanceFetcher(DefaultFetcher)(reference)
+}
+
+// EffectiveGuideline is a guideline in effect for a policy.
+type EffectiveGuideline struct {
+	Guideline Guideline
+	// Source identifies the guidance document and guideline this entry was imported from.
+	Source SingleMapping
+	// Enhancements are the guidelines that extend this guideline.
+	Enhancements []EffectiveGuideline
+	// Constraints are the policy constraints targeting this guideline.
+	Constraints []Constraint
+}
+
+// EffectiveGuidance is the result of applying a policy's guidance imports.
+type EffectiveGuidance struct {
+	// Families contains the families of every effective guideline.
+	Families []Family
+	// Guidelines contains the guidelines that do not extend another guideline, in import order.
+	// Extending guidelines are nested under their base as enhancements.
+	Guidelines []EffectiveGuideline
+}
+
+// ResolveGuidance fetches every guidance document in Imports.Guidance, removes excluded guidelines,
+// attaches constraints, and merges guidelines that use Extends into their base guideline.
+// Each GuidanceImport.ReferenceId must match a MappingReference in the policy metadata.
+// When a guideline extends a guideline in a document that the policy does not import, that document
+// is fetched through the extending document's mapping references and the base guideline is included.
+// Excluding a guideline also excludes the guidelines that extend it.
+func (p *Policy) ResolveGuidance(fetch GuidanceFetcher) (*EffectiveGuidance, error) {
+	r := &guidanceResolver{
+		fetch:    fetch,
+		entries:  make(map[guidelineKey]*guidanceEntry),
+		docs:     make(map[string]*GuidanceDocument),
+		excluded: make(map[string]map[string]bool),
+	}
+
+	var imported []importedGuidance
+	for _, guidanceImport := range p.Imports.Guidance {
+		reference, ok := findMappingReference(p.Metadata, guidanceImport.ReferenceId)
+		if !ok {
+			return nil, fmt.Errorf("no mapping reference found for guidance import %q", guidanceImport.ReferenceId)
+		}
+		doc, err := r.document(reference)
+		if err != nil {
+			return nil, err
+		}
+
+		excluded := make(map[string]bool)
+		for _, id := range guidanceImport.Exclusions {
+			if _, ok := findGuideline(doc, id); !ok {
+				return nil, fmt.Errorf("excluded guideline %q not found in guidance %q", id, guidanceImport.ReferenceId)
+			}
+			excluded[id] = true
+		}
+		r.excluded[guidanceImport.ReferenceId] = excluded
+
+		var guidelines []Guideline
+		for _, guideline := range doc.Guidelines {
+			if excluded[guideline.Id] || extendsExcluded(doc, guideline, excluded) {
+				continue
+			}
+			guidelines = append(guidelines, guideline)
+			r.add(guidanceImport.ReferenceId, doc, guideline)
+		}
+		imported = append(imported, importedGuidance{reference: guidanceImport.ReferenceId, doc: doc, guidelines: guidelines})
+
+		for _, constraint := range guidanceImport.Constraints {
+			entry, ok := r.entries[guidelineKey{guidanceImport.ReferenceId, constraint.TargetId}]
+			if !ok {
+				return nil, fmt.Errorf("constraint %q targets %q, which is not in guidance %q", constraint.Id, constraint.TargetId, guidanceImport.ReferenceId)
+			}
+			entry.constraints = append(entry.constraints, constraint)
+		}
+	}
+
+	for _, imp := range imported {
+		for _, guideline := range imp.guidelines {
+			if guideline.Extends == nil {
+				continue
+			}
+			base, err := r.base(imp.reference, imp.doc, guideline)
+			if err != nil {
+				return nil, err
+			}
+			entry := r.entries[guidelineKey{imp.reference, guideline.Id}]
+			if base == nil {
+				// The extended guideline is excluded by the import of another document.
+				entry.excluded = true
+				continue
+			}
+			entry.extends = true
+			base.enhancements = append(base.enhancements, entry)
+		}
+	}
+
+	effective := &EffectiveGuidance{}
+	familyIds := make(map[string]bool)
+	for _, entry := range r.order {
+		if entry.extends || entry.excluded {
+			continue
+		}
+		effective.Guidelines = append(effective.Guidelines, entry.effective())
+		if !familyIds[entry.guideline.Family] {
+			if family, ok := findGuidanceFamily(entry.doc, entry.guideline.Family); ok {
+				familyIds[family.Id] = true
+				effective.Families = append(effective.Families, family)
+			}
+		}
+	}
+	return effective, nil
+}
+
+type guidelineKey struct {
+	referenceId string
+	guidelineId string
+}
+
+type guidanceEntry struct {
+	guideline    Guideline
+	doc          *GuidanceDocument
+	source       SingleMapping
+	extends      bool
+	excluded     bool
+	enhancements []*guidanceEntry
+	constraints  []Constraint
+}
+
+func (e *guidanceEntry) effective() EffectiveGuideline {
+	effective := EffectiveGuideline{
+		Guideline:   e.guideline,
+		Source:      e.source,
+		Constraints: e.constraints,
+	}
+	for _, enhancement := range e.enhancements {
+		effective.Enhancements = append(effective.Enhancements, enhancement.effective())
+	}
+	return effective
+}
+
+type importedGuidance struct {
+	reference  string
+	doc        *GuidanceDocument
+	guidelines []Guideline
+}
+
+type guidanceResolver struct {
+	fetch   GuidanceFetcher
+	entries map[guidelineKey]*guidanceEntry
+	order   []*guidanceEntry
+	docs    map[string]*GuidanceDocument
+	// excluded holds the guideline ids excluded by each import, keyed by reference id.
+	excluded map[string]map[string]bool
+}
+
+// document fetches the guidance document for a reference once and caches it.
+func (r *guidanceResolver) document(reference MappingReference) (*GuidanceDocument, error) {
+	key := reference.Id + "@" + reference.Version + "@" + reference.Url
+	if doc, ok := r.docs[key]; ok {
+		return doc, nil
+	}
+	doc, err := r.fetch(reference)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching guidance %q: %w", reference.Id, err)
+	}
+	r.docs[key] = doc
+	return doc, nil
+}
+
+func (r *guidanceResolver) add(referenceId string, doc *GuidanceDocument, guideline Guideline) *guidanceEntry {
+	key := guidelineKey{referenceId, guideline.Id}
+	if entry, ok := r.entries[key]; ok {
+		return entry
+	}
+	entry := &guidanceEntry{
+		guideline: guideline,
+		doc:       doc,
+		source:    SingleMapping{ReferenceId: referenceId, EntryId: guideline.Id},
+	}
+	r.entries[key] = entry
+	r.order = append(r.order, entry)
+	return entry
+}
+
+// base returns the entry for the guideline extended by guideline, fetching it if needed.
+// It returns nil when the extended guideline is excluded by the import of its document, in
+// which case guideline is excluded as well.
+func (r *guidanceResolver) base(referenceId string, doc *GuidanceDocument, guideline Guideline) (*guidanceEntry, error) {
+	extends := guideline.Extends
+	if extends.ReferenceId == "" || extends.ReferenceId == referenceId {
+		base, ok := r.entries[guidelineKey{referenceId, extends.EntryId}]
+		if !ok {
+			return nil, fmt.Errorf("guideline %q extends %q, which is not in guidance %q", guideline.Id, extends.EntryId, referenceId)
+		}
+		return base, nil
+	}
+
+	if base, ok := r.entries[guidelineKey{extends.ReferenceId, extends.EntryId}]; ok {
+		return base, nil
+	}
+	if r.excluded[extends.ReferenceId][extends.EntryId] {
+		return nil, nil
+	}
+	reference, ok := findMappingReference(doc.Metadata, extends.ReferenceId)
+	if !ok {
+		return nil, fmt.Errorf("guideline %q extends reference-id %q, which has no mapping reference in guidance %q", guideline.Id, extends.ReferenceId, referenceId)
+	}
+	external, err := r.document(reference)
+	if err != nil {
+		return nil, err
+	}
+	extended, ok := findGuideline(external, extends.EntryId)
+	if !ok {
+		return nil, fmt.Errorf("guideline %q extends %q, which is not in guidance %q", guideline.Id, extends.EntryId, extends.ReferenceId)
+	}
+	return r.add(extends.ReferenceId, external, extended), nil
+}
+
+// extendsExcluded reports whether guideline extends, directly or transitively, an excluded guideline
+// in the same document.
+func extendsExcluded(doc *GuidanceDocument, guideline Guideline, excluded map[string]bool) bool {
+	seen := make(map[string]bool)
+	for guideline.Extends != nil && guideline.Extends.ReferenceId == "" && !seen[guideline.Id] {
+		seen[guideline.Id] = true
+		if excluded[guideline.Extends.EntryId] {
+			return true
+		}
+		next, ok := findGuideline(doc, guideline.Extends.EntryId)
+		if !ok {
+			return false
+		}
+		guideline = next
+	}
+	return false
+}
+
+func findGuideline(doc *GuidanceDocument, id string) (Guideline, bool) {
+	for _, guideline := range doc.Guidelines {
+		if guideline.Id == id {
+			return guideline, true
+		}
+	}
+	return Guideline{}, false
+}
+
+func findGuidanceFamily(doc *GuidanceDocument, id string) (Family, bool) {
+	for _, family := range doc.Families {
+		if family.Id == id {
+			return family, true
+		}
+	}
+	return Family{}, false
+}
@@ -0,0 +1,189 @@
+package gemara
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_ResolveGuidance_FromURL(t *testing.T) {
+	p := &Policy{
+		Metadata: Metadata{
+			MappingReferences: []MappingReference{
+				{Id: "EX-AI", Title: "Example Org AI Guidance", Version: "0.1.0", Url: "file://good-extended-guidance.yaml"},
+			},
+		},
+		Imports: Imports{
+			Guidance: []GuidanceImport{
+				{
+					ReferenceId: "EX-AI",
+					Constraints: []Constraint{{Id: "CON-1", TargetId: "EX-DET-100", Text: "Review the inventory quarterly."}},
+				},
+			},
+		},
+	}
+
+	effective, err := p.ResolveGuidance(NewGuidanceFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	require.Len(t, effective.Guidelines, 2)
+	inventory := effective.Guidelines[0]
+	assert.Equal(t, "EX-DET-100", inventory.Guideline.Id)
+	assert.Equal(t, SingleMapping{ReferenceId: "EX-AI", EntryId: "EX-DET-100"}, inventory.Source)
+	require.Len(t, inventory.Enhancements, 1)
+	assert.Equal(t, "EX-DET-100.1", inventory.Enhancements[0].Guideline.Id)
+	require.Len(t, inventory.Constraints, 1)
+	assert.Equal(t, "CON-1", inventory.Constraints[0].Id)
+
+	feedback := effective.Guidelines[1]
+	assert.Equal(t, "AIR-DET-011", feedback.Guideline.Id, "externally extended guidelines should be pulled in")
+	assert.Equal(t, SingleMapping{ReferenceId: "FINOS-AIR", EntryId: "AIR-DET-011"}, feedback.Source)
+	require.Len(t, feedback.Enhancements, 1)
+	assert.Equal(t, "EX-DET-011.1", feedback.Enhancements[0].Guideline.Id)
+
+	require.Len(t, effective.Families, 1)
+	assert.Equal(t, "DET", effective.Families[0].Id)
+}
+
+func TestPolicy_ResolveGuidance_ExcludedExternalBase(t *testing.T) {
+	p := &Policy{
+		Metadata: Metadata{
+			MappingReferences: []MappingReference{
+				{Id: "FINOS-AIR", Title: "AI Governance Framework", Version: "0.1.0", Url: "file://good-aigf.yaml"},
+				{Id: "EX-AI", Title: "Example Org AI Guidance", Version: "0.1.0", Url: "file://good-extended-guidance.yaml"},
+			},
+		},
+		Imports: Imports{
+			Guidance: []GuidanceImport{
+				{ReferenceId: "FINOS-AIR", Exclusions: []string{"AIR-DET-011"}},
+				{ReferenceId: "EX-AI"},
+			},
+		},
+	}
+
+	effective, err := p.ResolveGuidance(NewGuidanceFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	var ids []string
+	var collect func(guidelines []EffectiveGuideline)
+	collect = func(guidelines []EffectiveGuideline) {
+		for _, guideline := range guidelines {
+			ids = append(ids, guideline.Guideline.Id)
+			collect(guideline.Enhancements)
+		}
+	}
+	collect(effective.Guidelines)
+	assert.NotContains(t, ids, "AIR-DET-011", "a guideline excluded by one import is not pulled in by another")
+	assert.NotContains(t, ids, "EX-DET-011.1", "guidelines extending an excluded guideline are excluded")
+	assert.Contains(t, ids, "EX-DET-100.1")
+}
+
+func TestPolicy_ResolveGuidance(t *testing.T) {
+	docs := map[string]*GuidanceDocument{
+		"BASE": {
+			Families: []Family{{Id: "F1"}, {Id: "F2"}},
+			Guidelines: []Guideline{
+				{Id: "G1", Family: "F1"},
+				{Id: "G2", Family: "F2"},
+				{Id: "G2.1", Family: "F2", Extends: &SingleMapping{EntryId: "G2"}},
+				{Id: "G2.1.1", Family: "F2", Extends: &SingleMapping{EntryId: "G2.1"}},
+			},
+		},
+		"ORG": {
+			Metadata: Metadata{MappingReferences: []MappingReference{{Id: "BASE"}}},
+			Families: []Family{{Id: "F1"}},
+			Guidelines: []Guideline{
+				{Id: "ORG-1", Family: "F1", Extends: &SingleMapping{ReferenceId: "BASE", EntryId: "G1"}},
+				{Id: "ORG-2", Family: "F1", Extends: &SingleMapping{ReferenceId: "BASE", EntryId: "G9"}},
+			},
+		},
+	}
+	fetch := func(reference MappingReference) (*GuidanceDocument, error) {
+		if doc, ok := docs[reference.Id]; ok {
+			return doc, nil
+		}
+		return nil, fmt.Errorf("not found")
+	}
+	policy := func(imports ...GuidanceImport) *Policy {
+		p := &Policy{Imports: Imports{Guidance: imports}}
+		for _, imp := range imports {
+			p.Metadata.MappingReferences = append(p.Metadata.MappingReferences, MappingReference{Id: imp.ReferenceId})
+		}
+		return p
+	}
+	ids := func(guidelines []EffectiveGuideline) []string {
+		var out []string
+		for _, g := range guidelines {
+			out = append(out, g.Guideline.Id)
+		}
+		return out
+	}
+
+	t.Run("Nested enhancements", func(t *testing.T) {
+		effective, err := policy(GuidanceImport{ReferenceId: "BASE"}).ResolveGuidance(fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"G1", "G2"}, ids(effective.Guidelines))
+		assert.Equal(t, []string{"G2.1"}, ids(effective.Guidelines[1].Enhancements))
+		assert.Equal(t, []string{"G2.1.1"}, ids(effective.Guidelines[1].Enhancements[0].Enhancements))
+		assert.Len(t, effective.Families, 2)
+	})
+
+	t.Run("Exclusions remove extending guidelines", func(t *testing.T) {
+		effective, err := policy(GuidanceImport{ReferenceId: "BASE", Exclusions: []string{"G2"}}).ResolveGuidance(fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"G1"}, ids(effective.Guidelines))
+		assert.Len(t, effective.Families, 1)
+	})
+
+	t.Run("Extending an imported document", func(t *testing.T) {
+		effective, err := policy(
+			GuidanceImport{ReferenceId: "BASE", Exclusions: []string{"G2"}},
+			GuidanceImport{ReferenceId: "ORG", Exclusions: []string{"ORG-2"}},
+		).ResolveGuidance(fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"G1"}, ids(effective.Guidelines))
+		assert.Equal(t, []string{"ORG-1"}, ids(effective.Guidelines[0].Enhancements))
+		assert.Equal(t, SingleMapping{ReferenceId: "ORG", EntryId: "ORG-1"}, effective.Guidelines[0].Enhancements[0].Source)
+	})
+
+	errorTests := []struct {
+		name    string
+		policy  *Policy
+		wantErr string
+	}{
+		{
+			name:    "Missing mapping reference",
+			policy:  &Policy{Imports: Imports{Guidance: []GuidanceImport{{ReferenceId: "BASE"}}}},
+			wantErr: `no mapping reference found for guidance import "BASE"`,
+		},
+		{
+			name:    "Fetch failure",
+			policy:  policy(GuidanceImport{ReferenceId: "NOWHERE"}),
+			wantErr: "not found",
+		},
+		{
+			name:    "Unknown exclusion",
+			policy:  policy(GuidanceImport{ReferenceId: "BASE", Exclusions: []string{"G9"}}),
+			wantErr: `excluded guideline "G9" not found`,
+		},
+		{
+			name:    "Constraint on an excluded guideline",
+			policy:  policy(GuidanceImport{ReferenceId: "BASE", Exclusions: []string{"G1"}, Constraints: []Constraint{{Id: "C", TargetId: "G1"}}}),
+			wantErr: `constraint "C" targets "G1"`,
+		},
+		{
+			name:    "Extended guideline does not exist",
+			policy:  policy(GuidanceImport{ReferenceId: "ORG"}),
+			wantErr: `guideline "ORG-2" extends "G9"`,
+		},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.policy.ResolveGuidance(fetch)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package loaders
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -70,7 +72,10 @@ func decodeYAMLFromFile(filePath string, target interface{}) error {
 			log.Printf("failed to close file: %v", err)
 		}
 	}()
-	return decodeYAMLFromReader(file, target)
+	if err := decodeYAMLFromReader(file, target); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	return nil
 }
 
 func decodeYAMLFromReader(reader io.Reader, target interface{}) error {
@@ -107,18 +112,72 @@ func decodeJSONFromFile(filePath string, target interface{}) error {
 			log.Printf("failed to close file: %v", err)
 		}
 	}()
-	return decodeJSONFromReader(file, target)
+	if err := decodeJSONFromReader(file, target); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	return nil
 }
 
 func decodeJSONFromReader(reader io.Reader, target interface{}) error {
-	decoder := json.NewDecoder(reader)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("error reading JSON: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(target); err != nil {
-		return fmt.Errorf("error decoding JSON: %w", err)
+		line, column := jsonErrorPosition(data, decoder, err)
+		return fmt.Errorf("error decoding JSON: %w", &PositionError{Line: line, Column: column, Err: err})
 	}
 	return nil
 }
 
+// PositionError is a decoding error at a line and column of the input.
+type PositionError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("[%d:%d] %v", e.Line, e.Column, e.Err)
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
+// jsonErrorPosition returns the line and column of a decoding error, which encoding/json
+// only reports as a byte offset, if at all.
+func jsonErrorPosition(data []byte, decoder *json.Decoder, err error) (line, column int) {
+	offset := decoder.InputOffset()
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	consumed := data[:offset]
+	line = bytes.Count(consumed, []byte("\n")) + 1
+	column = len(consumed) - bytes.LastIndexByte(consumed, '\n')
+	return line, column
+}
+
+// DecodeYAML decodes YAML from the reader into the provided target.
+func DecodeYAML(reader io.Reader, target interface{}) error {
+	return decodeYAMLFromReader(reader, target)
+}
+
+// DecodeJSON decodes JSON from the reader into the provided target, rejecting unknown fields.
+func DecodeJSON(reader io.Reader, target interface{}) error {
+	return decodeJSONFromReader(reader, target)
+}
+
 // MarshalYAML marshals an object to YAML bytes.
 func MarshalYAML(v interface{}) ([]byte, error) {
 	return yaml.Marshal(v)
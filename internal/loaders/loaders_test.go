@@ -1,6 +1,7 @@
 package loaders
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -90,6 +91,19 @@ func TestDecodeJSONFromReader(t *testing.T) {
 	}
 }
 
+func TestDecodeJSONFromReader_ErrorPosition(t *testing.T) {
+	reader := strings.NewReader("{\n  \"field\": 42\n}")
+	var target dummyStruct
+	err := decodeJSONFromReader(reader, &target)
+	if err == nil || !strings.Contains(err.Error(), "[2:14]") {
+		t.Errorf("decodeJSONFromReader() error = %v, want position [2:14]", err)
+	}
+	var positionErr *PositionError
+	if !errors.As(err, &positionErr) || positionErr.Line != 2 || positionErr.Column != 14 {
+		t.Errorf("decodeJSONFromReader() error = %v, want a PositionError at 2:14", err)
+	}
+}
+
 func TestMarshalUnmarshalYAML(t *testing.T) {
 	obj := dummyStruct{Field: "value"}
 	bytes, err := MarshalYAML(obj)
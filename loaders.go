@@ -2,7 +2,6 @@ package gemara
 
 import (
 	"fmt"
-	"path"
 
 	"github.com/ossf/gemara/internal/loaders"
 )
@@ -10,22 +9,7 @@ import (
 // LoadFile loads data from a YAML or JSON file at the provided path.
 // If run multiple times for the same data type, this method will override previous data.
 func (p *Policy) LoadFile(sourcePath string) error {
-	ext := path.Ext(sourcePath)
-	switch ext {
-	case ".yaml", ".yml":
-		err := loaders.LoadYAML(sourcePath, p)
-		if err != nil {
-			return err
-		}
-	case ".json":
-		err := loaders.LoadJSON(sourcePath, p)
-		if err != nil {
-			return fmt.Errorf("error loading json: %w", err)
-		}
-	default:
-		return fmt.Errorf("unsupported file extension: %s", ext)
-	}
-	return nil
+	return loadFile(sourcePath, p)
 }
 
 // LoadFiles loads data from any number of YAML or JSON files at the provided paths.
@@ -50,22 +34,7 @@ func (g *GuidanceDocument) LoadFiles(sourcePaths []string) error {
 // sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
 // If run multiple times for the same data type, this method will override previous data.
 func (g *GuidanceDocument) LoadFile(sourcePath string) error {
-	ext := path.Ext(sourcePath)
-	switch ext {
-	case ".yaml", ".yml":
-		err := loaders.LoadYAML(sourcePath, g)
-		if err != nil {
-			return err
-		}
-	case ".json":
-		err := loaders.LoadJSON(sourcePath, g)
-		if err != nil {
-			return fmt.Errorf("error loading json: %w", err)
-		}
-	default:
-		return fmt.Errorf("unsupported file extension: %s", ext)
-	}
-	return nil
+	return loadFile(sourcePath, g)
 }
 
 // LoadFiles loads data from any number of YAML or JSON files at the provided paths.
@@ -96,24 +65,69 @@ func (c *Catalog) LoadFiles(sourcePaths []string) error {
 // sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
 // If run multiple times for the same data type, this method will override previous data.
 func (c *Catalog) LoadFile(sourcePath string) error {
-	ext := path.Ext(sourcePath)
-	switch ext {
-	case ".yaml", ".yml":
-		err := loaders.LoadYAML(sourcePath, c)
-		if err != nil {
+	return loadFile(sourcePath, c)
+}
+
+// LoadFiles loads data from any number of YAML or JSON files at the provided paths.
+// sourcePath are expected to be file or https URIs in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+// If run multiple times, this method will append new data to previous data.
+func (e *EvaluationLog) LoadFiles(sourcePaths []string) error {
+	for _, sourcePath := range sourcePaths {
+		log := &EvaluationLog{}
+		if err := log.LoadFile(sourcePath); err != nil {
 			return err
 		}
-	case ".json":
-		err := loaders.LoadJSON(sourcePath, c)
-		if err != nil {
-			return fmt.Errorf("error loading json: %w", err)
+		if e.Metadata.Id == "" {
+			e.Metadata = log.Metadata
 		}
-	default:
-		return fmt.Errorf("unsupported file extension: %s", ext)
+		e.Evaluations = append(e.Evaluations, log.Evaluations...)
 	}
 	return nil
 }
 
+// LoadFile loads data from a single YAML or JSON file at the provided path into the EvaluationLog.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+// If run multiple times for the same data type, this method will override previous data.
+func (e *EvaluationLog) LoadFile(sourcePath string) error {
+	return loadFile(sourcePath, e)
+}
+
+// LoadOption defines an option to tune how LoadEvaluationLog loads a log.
+type LoadOption func(opts *loadOpts)
+
+type loadOpts struct {
+	registry *StepRegistry
+}
+
+// WithStepRegistry is a LoadOption that replaces every step of the loaded log with the step
+// registered under the same name in registry, as StepRegistry.Rehydrate does, so that the log
+// can be run again.
+func WithStepRegistry(registry *StepRegistry) LoadOption {
+	return func(opts *loadOpts) {
+		opts.registry = registry
+	}
+}
+
+// LoadEvaluationLog loads an EvaluationLog from a single YAML or JSON file at the provided path,
+// as EvaluationLog.LoadFile does. Its steps keep their names but report Unknown when run, unless
+// they are resolved with WithStepRegistry, in which case every step must be registered.
+func LoadEvaluationLog(sourcePath string, opts ...LoadOption) (*EvaluationLog, error) {
+	options := &loadOpts{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	log := &EvaluationLog{}
+	if err := log.LoadFile(sourcePath); err != nil {
+		return nil, err
+	}
+	if options.registry != nil {
+		if err := options.registry.Rehydrate(log); err != nil {
+			return nil, fmt.Errorf("%s: %w", sourcePath, err)
+		}
+	}
+	return log, nil
+}
+
 // LoadNestedCatalog loads a YAML file containing a nested catalog.
 // Only supports a single layer of nesting.
 // Accepts file URIs with the 'file:///' prefix.
@@ -124,7 +138,7 @@ func (c *Catalog) LoadNestedCatalog(sourcePath, fieldName string) error {
 		return fmt.Errorf("fieldName cannot be empty")
 	}
 	var yamlData map[string]interface{}
-	err := loaders.LoadYAML(sourcePath, &yamlData)
+	err := decodeYAMLFrom(sourcePath, &yamlData)
 	if err != nil {
 		return fmt.Errorf("error decoding YAML: %w (%s)", err, sourcePath)
 	}
@@ -143,3 +157,23 @@ func (c *Catalog) LoadNestedCatalog(sourcePath, fieldName string) error {
 	}
 	return nil
 }
+
+// loadFile decodes the YAML or JSON document at sourcePath into target, choosing the format
+// from its extension and retrieving it with DefaultFetcher.
+func loadFile(sourcePath string, target interface{}) error {
+	if _, err := FormatFromPath(sourcePath); err != nil {
+		return err
+	}
+	return Decode(FromURI(sourcePath, DefaultFetcher), target)
+}
+
+// decodeYAMLFrom decodes the document at sourcePath as YAML, whatever its extension,
+// retrieving it with DefaultFetcher.
+func decodeYAMLFrom(sourcePath string, target interface{}) error {
+	reader, err := DefaultFetcher.Fetch(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	return loaders.DecodeYAML(reader, target)
+}
@@ -4,12 +4,18 @@ package gemara
 // - PolicyDocument.LoadFile
 // - GuidanceDocument.LoadFile and LoadFiles
 // - Catalog.LoadFile, LoadFiles, and LoadNestedCatalog
+// - EvaluationLog.LoadFile and LoadFiles
 //
 // Test data is pulled from ./test-data/
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/goccy/go-yaml"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,6 +50,11 @@ func TestPolicy_LoadFile(t *testing.T) {
 			sourcePath: "file://test-data/good-security-policy.yml",
 			wantErr:    false,
 		},
+		{
+			name:       "Good YAML — OSPS Policy",
+			sourcePath: "file://test-data/good-osps-policy.yaml",
+			wantErr:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -498,3 +509,113 @@ func TestCatalog_LoadNestedCatalog(t *testing.T) {
 		})
 	}
 }
+
+// ============================================================================
+// EvaluationLog Tests
+// ============================================================================
+
+func TestEvaluationLog_LoadFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourcePath string
+		wantErr    bool
+	}{
+		{
+			name:       "Bad path",
+			sourcePath: "file://bad-path.yaml",
+			wantErr:    true,
+		},
+		{
+			name:       "Bad YAML",
+			sourcePath: "file://test-data/bad.yaml",
+			wantErr:    true,
+		},
+		{
+			name:       "Good YAML — Evaluation Log",
+			sourcePath: "file://test-data/good-evaluation-log.yaml",
+			wantErr:    false,
+		},
+		{
+			name:       "Unsupported file extension",
+			sourcePath: "file://test-data/unsupported.txt",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EvaluationLog{}
+			err := e.LoadFile(tt.sourcePath)
+
+			if tt.wantErr {
+				assert.Error(t, err, "expected error but got none")
+			} else {
+				require.NoError(t, err, "unexpected error loading file")
+				assert.Equal(t, "osps-baseline-evaluation", e.Metadata.Id)
+				require.Len(t, e.Evaluations, 3)
+				assert.Equal(t, Failed, e.Evaluations[1].Result)
+				require.Len(t, e.Evaluations[1].AssessmentLogs, 2)
+				log := e.Evaluations[1].AssessmentLogs[1]
+				assert.Equal(t, Failed, log.Result)
+				assert.Equal(t, High, log.ConfidenceLevel)
+				assert.Equal(t, "github.com/revanite-io/pvtr-github-repo/evaluation_plans/osps/access_control.branchProtectionPreventsDeletion",
+					log.Steps[0].Name())
+			}
+		})
+	}
+}
+
+func TestEvaluationLog_LoadFiles_AppendsData(t *testing.T) {
+	single := &EvaluationLog{}
+	require.NoError(t, single.LoadFile("file://test-data/good-evaluation-log.yaml"))
+
+	multi := &EvaluationLog{}
+	err := multi.LoadFiles([]string{
+		"file://test-data/good-evaluation-log.yaml",
+		"file://test-data/good-evaluation-log.yaml",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, single.Metadata, multi.Metadata,
+		"first log's metadata should be preserved")
+	assert.Equal(t, len(single.Evaluations)*2, len(multi.Evaluations),
+		"evaluations should be appended across multiple files")
+}
+
+func TestEvaluationLog_RoundTrip(t *testing.T) {
+	original := &EvaluationLog{}
+	require.NoError(t, original.LoadFile("file://test-data/good-evaluation-log.yaml"))
+
+	tests := []struct {
+		name    string
+		file    string
+		marshal func(interface{}) ([]byte, error)
+	}{
+		{
+			name:    "YAML",
+			file:    "evaluation-log.yaml",
+			marshal: yaml.Marshal,
+		},
+		{
+			name:    "JSON",
+			file:    "evaluation-log.json",
+			marshal: json.Marshal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.marshal(original)
+			require.NoError(t, err)
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, data, 0600))
+
+			reloaded := &EvaluationLog{}
+			require.NoError(t, reloaded.LoadFile("file://"+path))
+
+			again, err := tt.marshal(reloaded)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+		})
+	}
+}
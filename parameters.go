@@ -0,0 +1,105 @@
+package gemara
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParameterValues supplies the values of assessment plan parameters, keyed by assessment plan id
+// and then by parameter id, so that one policy can be evaluated with the values of several
+// environments. In YAML it is written as:
+//
+//	EX-PLAN-AC-03:
+//	  minimum-reviewers: "2"
+type ParameterValues map[string]map[string]string
+
+// LoadFile loads the values from a YAML or JSON file at the provided path.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+func (v *ParameterValues) LoadFile(sourcePath string) error {
+	return loadFile(sourcePath, v)
+}
+
+// BindParameters returns the value of every parameter of plan, taken from values or, when values
+// does not set it, from Parameter.Value in the policy. The error lists every parameter without a
+// value, every value that is not one of the parameter's AcceptedValues, and every entry of values
+// that is not a parameter of plan.
+func BindParameters(plan AssessmentPlan, values map[string]string) (map[string]string, error) {
+	var problems []string
+	known := make(map[string]bool, len(plan.Parameters))
+	bound := make(map[string]string, len(plan.Parameters))
+	for _, parameter := range plan.Parameters {
+		known[parameter.Id] = true
+		value, ok := values[parameter.Id]
+		if !ok {
+			value = parameter.Value
+		}
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("parameter %s has no value", parameter.Id))
+			continue
+		}
+		if !acceptsValue(parameter, value) {
+			problems = append(problems, fmt.Sprintf("parameter %s does not accept %q (accepted values: %s)",
+				parameter.Id, value, strings.Join(parameter.AcceptedValues, ", ")))
+			continue
+		}
+		bound[parameter.Id] = value
+	}
+	var unknown []string
+	for id := range values {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		problems = append(problems, fmt.Sprintf("%s is not a parameter of the plan", id))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("assessment plan %s: %s", plan.Id, strings.Join(problems, "; "))
+	}
+	return bound, nil
+}
+
+func acceptsValue(parameter Parameter, value string) bool {
+	if len(parameter.AcceptedValues) == 0 {
+		return true
+	}
+	for _, accepted := range parameter.AcceptedValues {
+		if value == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// BindParameters binds the parameters of plan with the package-level BindParameters and records
+// them in Parameters, which RunContext passes to every step in StepInput.
+func (a *AssessmentLog) BindParameters(plan AssessmentPlan, values map[string]string) error {
+	bound, err := BindParameters(plan, values)
+	if err != nil {
+		return err
+	}
+	if len(bound) == 0 {
+		bound = nil
+	}
+	a.Parameters = bound
+	return nil
+}
+
+// ParameterizedStep is a function type like AssessmentStep that also receives the bound
+// parameters of the assessment, keyed by parameter id. It adapts the function to the Step
+// interface and is named after the fully qualified name of the function.
+type ParameterizedStep func(payload interface{}, parameters map[string]string) (Result, string, ConfidenceLevel)
+
+// Name returns the fully qualified name of the function.
+func (ps ParameterizedStep) Name() string {
+	return functionName(ps)
+}
+
+// Run calls ps with the payload and parameters of input.
+func (ps ParameterizedStep) Run(_ context.Context, input StepInput) StepResult {
+	result, message, confidence := ps(input.Payload, input.Parameters)
+	return StepResult{Result: result, Message: message, ConfidenceLevel: confidence}
+}
@@ -0,0 +1,148 @@
+package gemara
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reviewersPlan = AssessmentPlan{
+	Id:            "EX-PLAN-AC-03",
+	RequirementId: "OSPS-AC-03.01",
+	Parameters: []Parameter{
+		{Id: "minimum-reviewers", AcceptedValues: []string{"1", "2", "3"}, Value: "2"},
+		{Id: "branch"},
+	},
+}
+
+func TestBindParameters(t *testing.T) {
+	bound, err := BindParameters(reviewersPlan, map[string]string{"branch": "main"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"minimum-reviewers": "2", "branch": "main"}, bound, "policy values are the default")
+
+	bound, err = BindParameters(reviewersPlan, map[string]string{"minimum-reviewers": "3", "branch": "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", bound["minimum-reviewers"])
+
+	_, err = BindParameters(reviewersPlan, map[string]string{"minimum-reviewers": "5", "reviewers": "2"})
+	assert.EqualError(t, err, `assessment plan EX-PLAN-AC-03: parameter minimum-reviewers does not accept "5" (accepted values: 1, 2, 3); `+
+		`parameter branch has no value; reviewers is not a parameter of the plan`)
+}
+
+func TestParameterValues_LoadFile(t *testing.T) {
+	values := ParameterValues{}
+	require.NoError(t, values.LoadFile("file://test-data/good-parameter-values.yaml"))
+	assert.Equal(t, ParameterValues{"EX-PLAN-AC-03": {"minimum-reviewers": "3"}}, values)
+
+	assert.EqualError(t, values.LoadFile("file://test-data/good-parameter-values.txt"), "unsupported file extension: .txt")
+}
+
+func TestStepInput_Parameters(t *testing.T) {
+	minimumReviewers := StepFunc(func(_ context.Context, input StepInput) StepResult {
+		if input.Parameters["minimum-reviewers"] != "3" {
+			return StepResult{Result: Failed, Message: "expected the bound minimum", ConfidenceLevel: High}
+		}
+		return StepResult{Result: Passed, Message: "minimum bound", ConfidenceLevel: High}
+	})
+	branch := ParameterizedStep(func(_ interface{}, parameters map[string]string) (Result, string, ConfidenceLevel) {
+		if parameters["branch"] != "main" {
+			return Failed, "expected the bound branch", High
+		}
+		return Passed, "branch bound", High
+	})
+	assessment, err := newAssessment("OSPS-AC-03.01", "description", testingApplicability, StepList{minimumReviewers, branch})
+	require.NoError(t, err)
+	require.NoError(t, assessment.BindParameters(reviewersPlan, map[string]string{"minimum-reviewers": "3", "branch": "main"}))
+
+	assert.Equal(t, Passed, assessment.Run(nil))
+	assert.EqualValues(t, 2, assessment.StepsExecuted)
+
+	assert.Error(t, assessment.BindParameters(reviewersPlan, nil))
+	assert.Equal(t, map[string]string{"minimum-reviewers": "3", "branch": "main"}, assessment.Parameters, "a failed binding keeps the previous parameters")
+}
+
+func TestProcedure_Parameters(t *testing.T) {
+	procedure := Procedure{
+		Id:            "reviewers",
+		RequirementId: "OSPS-AC-03.01",
+		Assertions: []Assertion{{
+			Path:       "$.required-reviewers",
+			Parameters: map[string]string{"minimum": "minimum-reviewers"},
+		}},
+	}
+	step, err := procedure.Compile()
+	require.NoError(t, err)
+	target := map[string]interface{}{"required-reviewers": 2}
+
+	result := step.Run(context.Background(), StepInput{Payload: target, Parameters: map[string]string{"minimum-reviewers": "2"}})
+	assert.Equal(t, Passed, result.Result)
+
+	result = step.Run(context.Background(), StepInput{Payload: target, Parameters: map[string]string{"minimum-reviewers": "3"}})
+	assert.Equal(t, Failed, result.Result)
+	assert.Equal(t, "$.required-reviewers: expected at least 3, got 2", result.Message)
+
+	result = step.Run(context.Background(), StepInput{Payload: target, Parameters: map[string]string{"minimum-reviewers": "two"}})
+	assert.Equal(t, Unknown, result.Result)
+	assert.Equal(t, `$.required-reviewers: parameter minimum-reviewers is not a valid minimum value: "two"`, result.Message)
+
+	result = step.Run(context.Background(), StepInput{Payload: target})
+	assert.Equal(t, Unknown, result.Result)
+	assert.Equal(t, "$.required-reviewers: parameter minimum-reviewers is not bound", result.Message)
+
+	procedure.Assertions[0].Parameters = map[string]string{"at-least": "minimum-reviewers"}
+	_, err = procedure.Compile()
+	assert.EqualError(t, err, `procedure reviewers: assertions[0]: parameters: "at-least" is not a condition`)
+}
+
+func TestPluginStep_Parameters(t *testing.T) {
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{
+		helperPlugin("echo", WithPluginParameters(map[string]interface{}{"minimum": 1})),
+	})
+	require.NoError(t, err)
+	plan := AssessmentPlan{Id: "EX-PLAN-AC-01", Parameters: []Parameter{{Id: "minimum"}}}
+	require.NoError(t, assessment.BindParameters(plan, map[string]string{"minimum": "2"}))
+
+	// The helper plugin passes only when it receives the number 2, not the bound string.
+	assert.Equal(t, Failed, assessment.Run(map[string]interface{}{"mfa": true}))
+}
+
+func TestBuildEvaluationLog_ParameterValues(t *testing.T) {
+	catalog, policy := loadPlanFixtures(t)
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep)
+	registry.Assign("OSPS-AC-03.01", AssessmentStep(passingAssessmentStep).String())
+
+	values := ParameterValues{}
+	require.NoError(t, values.LoadFile("file://test-data/good-parameter-values.yaml"))
+	log, diagnostics := BuildEvaluationLog(planMetadata, catalog, registry, WithPolicy(policy), WithParameterValues(values))
+	assert.Empty(t, diagnostics.Err())
+	require.Len(t, log.Evaluations, 1)
+	assert.Equal(t, map[string]string{"minimum-reviewers": "3"}, log.Evaluations[0].AssessmentLogs[0].Parameters)
+
+	values["EX-PLAN-AC-03"]["minimum-reviewers"] = "0"
+	_, diagnostics = BuildEvaluationLog(planMetadata, catalog, registry, WithPolicy(policy), WithParameterValues(values))
+	assert.Equal(t, map[string]DiagnosticCode{
+		"adherence.assessment-plans[0]":            CodeMissingSteps,
+		"adherence.assessment-plans[1].parameters": CodeInvalidParameter,
+	}, codes(diagnostics))
+}
+
+func TestValidate_Parameters(t *testing.T) {
+	policy := &Policy{}
+	require.NoError(t, policy.LoadFile("file://test-data/good-osps-policy.yaml"))
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+	assert.Equal(t, map[string]string{"minimum-reviewers": "2"}, log.Evaluations[1].AssessmentLogs[0].Parameters)
+
+	log.Evaluations[1].AssessmentLogs[0].Parameters["minimum-reviewers"] = "4"
+	assert.Equal(t, map[string]DiagnosticCode{
+		"evaluations[1].assessment-logs[0].parameters": CodeInvalidParameter,
+	}, codes(log.Validate(WithPolicies(policy))))
+
+	policy.Adherence.AssessmentPlans[1].Parameters[0].Value = "4"
+	assert.Equal(t, map[string]DiagnosticCode{
+		"adherence.assessment-plans[1].parameters[0].value": CodeInvalidParameter,
+	}, codes(policy.Validate()))
+}
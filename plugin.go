@@ -0,0 +1,234 @@
+package gemara
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PluginProtocolVersion is the version of the plugin protocol implemented by PluginStep.
+//
+// A plugin is an executable that assesses a single requirement. The runner starts it, writes a
+// PluginRequest as JSON to its standard input and closes it. The plugin writes a PluginResponse
+// as JSON to its standard output and exits with status 0. The plugin chooses one of the
+// versions offered in the request and reports it in the response; a response in any other
+// version is rejected, as is a response longer than 4 MiB. When the plugin fails, the start of
+// what it wrote to standard error is included in the message of the step; otherwise standard
+// error is discarded, so diagnostics worth keeping belong in the response message or evidence.
+const PluginProtocolVersion = "1"
+
+// PluginRequest is sent to a plugin on its standard input.
+type PluginRequest struct {
+	// ProtocolVersions lists the protocol versions the runner supports.
+	ProtocolVersions []string `json:"protocol-versions"`
+
+	RequirementId string `json:"requirement-id"`
+
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Payload is the target data passed to the step, encoded as JSON.
+	Payload interface{} `json:"payload"`
+}
+
+// PluginResponse is read from a plugin's standard output.
+type PluginResponse struct {
+	// ProtocolVersion is the protocol version the plugin chose from the request.
+	ProtocolVersion string `json:"protocol-version"`
+
+	Result Result `json:"result"`
+
+	Message string `json:"message"`
+
+	ConfidenceLevel ConfidenceLevel `json:"confidence-level,omitempty"`
+
+	// Evidence justifies the result. It is recorded on the assessment running the plugin.
+	Evidence []Evidence `json:"evidence,omitempty"`
+}
+
+// PluginOption defines an option to configure a step created with PluginStep.
+type PluginOption func(o *pluginOptions)
+
+type pluginOptions struct {
+	name       string
+	args       []string
+	env        []string
+	timeout    time.Duration
+	parameters map[string]interface{}
+}
+
+// WithPluginArgs is a PluginOption that passes args to the plugin executable.
+func WithPluginArgs(args ...string) PluginOption {
+	return func(o *pluginOptions) {
+		o.args = args
+	}
+}
+
+// WithPluginEnv is a PluginOption that adds environment variables, in the form KEY=value,
+// to the environment the plugin inherits.
+func WithPluginEnv(env ...string) PluginOption {
+	return func(o *pluginOptions) {
+		o.env = env
+	}
+}
+
+// WithPluginTimeout is a PluginOption that kills the plugin if it has not answered within timeout.
+// It applies in addition to the timeouts set with WithStepTimeout and WithAssessmentTimeout.
+func WithPluginTimeout(timeout time.Duration) PluginOption {
+	return func(o *pluginOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithPluginParameters is a PluginOption that sets the parameters sent in every request.
+// The bound parameters of the assessment running the plugin, see StepInput, take precedence.
+func WithPluginParameters(parameters map[string]interface{}) PluginOption {
+	return func(o *pluginOptions) {
+		o.parameters = parameters
+	}
+}
+
+// WithPluginName is a PluginOption that sets the step name reported by Step.Name,
+// which defaults to the path of the executable.
+func WithPluginName(name string) PluginOption {
+	return func(o *pluginOptions) {
+		o.name = name
+	}
+}
+
+// maxPluginStderr bounds how much of a plugin's standard error is kept for messages.
+const maxPluginStderr = 4096
+
+// maxPluginStdout bounds the size of a plugin's response.
+const maxPluginStdout = 4 << 20
+
+// PluginStep returns a Step that runs the plugin executable at path to assess
+// requirementId, using the protocol described by PluginProtocolVersion. The plugin is killed
+// when the assessment is cancelled or times out. A plugin that cannot be started, exits with
+// a non-zero status or answers with an invalid response is reported as Unknown, with its
+// standard error in the message.
+func PluginStep(path string, requirementId string, opts ...PluginOption) Step {
+	options := &pluginOptions{name: path}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &pluginStep{path: path, requirementId: requirementId, options: options}
+}
+
+type pluginStep struct {
+	path          string
+	requirementId string
+	options       *pluginOptions
+}
+
+func (p *pluginStep) Name() string { return p.options.name }
+
+func (p *pluginStep) Run(ctx context.Context, input StepInput) StepResult {
+	parameters := p.options.parameters
+	if bound := input.Parameters; len(bound) > 0 {
+		parameters = make(map[string]interface{}, len(p.options.parameters)+len(bound))
+		for id, value := range p.options.parameters {
+			parameters[id] = value
+		}
+		for id, value := range bound {
+			parameters[id] = value
+		}
+	}
+	request, err := json.Marshal(PluginRequest{
+		ProtocolVersions: []string{PluginProtocolVersion},
+		RequirementId:    p.requirementId,
+		Parameters:       parameters,
+		Payload:          input.Payload,
+	})
+	if err != nil {
+		return p.unknown(fmt.Sprintf("plugin %s: error encoding request: %v", p.options.name, err))
+	}
+
+	if p.options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, p.options.timeout, fmt.Errorf("plugin timeout of %s exceeded", p.options.timeout))
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, p.path, p.options.args...)
+	if len(p.options.env) > 0 {
+		cmd.Env = append(cmd.Environ(), p.options.env...)
+	}
+	// Do not wait for children that inherited the output pipes once the plugin is killed.
+	cmd.WaitDelay = time.Second
+	stdout := &limitedBuffer{limit: maxPluginStdout}
+	stderr := &limitedBuffer{limit: maxPluginStderr}
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = context.Cause(ctx)
+		}
+		return p.unknown(p.failure(fmt.Sprintf("failed: %v", err), stderr))
+	}
+
+	if stdout.truncated {
+		return p.unknown(p.failure(fmt.Sprintf("wrote a response larger than %d bytes", maxPluginStdout), stderr))
+	}
+
+	var response PluginResponse
+	decoder := json.NewDecoder(&stdout.buf)
+	if err := decoder.Decode(&response); err != nil {
+		return p.unknown(p.failure(fmt.Sprintf("returned an invalid response: %v", err), stderr))
+	}
+	if response.ProtocolVersion != PluginProtocolVersion {
+		return p.unknown(p.failure(fmt.Sprintf("answered with unsupported protocol version %q, expected %q",
+			response.ProtocolVersion, PluginProtocolVersion), stderr))
+	}
+	if response.Result == NotRun {
+		return p.unknown(p.failure("returned no result", stderr))
+	}
+	return StepResult{
+		Result:          response.Result,
+		Message:         response.Message,
+		ConfidenceLevel: response.ConfidenceLevel,
+		Evidence:        response.Evidence,
+	}
+}
+
+func (p *pluginStep) unknown(message string) StepResult {
+	return StepResult{Result: Unknown, Message: message, ConfidenceLevel: Undetermined}
+}
+
+// failure formats a message for a plugin that did not produce a usable response.
+func (p *pluginStep) failure(problem string, stderr *limitedBuffer) string {
+	message := fmt.Sprintf("plugin %s %s", p.options.name, problem)
+	if output := strings.TrimSpace(stderr.String()); output != "" {
+		message += "\nstderr: " + output
+	}
+	return message
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.buf.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "..."
+	}
+	return b.buf.String()
+}
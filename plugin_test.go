@@ -0,0 +1,132 @@
+package gemara
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPluginHelperProcess is not a real test. It is started by the plugin tests as the plugin
+// executable, and behaves as requested by GEMARA_TEST_PLUGIN.
+func TestPluginHelperProcess(t *testing.T) {
+	mode := os.Getenv("GEMARA_TEST_PLUGIN")
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	var request PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintf(os.Stderr, "bad request: %v", err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "echo":
+		target, _ := request.Payload.(map[string]interface{})
+		result := Failed
+		if target["mfa"] == true && request.Parameters["minimum"] == float64(2) {
+			result = Passed
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			"protocol-version": request.ProtocolVersions[0],
+			"result":           result,
+			"message":          "checked " + request.RequirementId,
+			"confidence-level": "High",
+			"evidence":         []interface{}{map[string]interface{}{"description": "organization settings", "content": "mfa: enabled"}},
+		})
+	case "crash":
+		fmt.Fprint(os.Stderr, "token expired")
+		os.Exit(3)
+	case "garbage":
+		fmt.Fprint(os.Stdout, "not json")
+	case "version":
+		fmt.Fprint(os.Stdout, `{"protocol-version": "99", "result": "Passed"}`)
+	case "flood":
+		fmt.Fprint(os.Stdout, strings.Repeat(" ", maxPluginStdout))
+		fmt.Fprint(os.Stdout, `{"protocol-version": "1", "result": "Passed"}`)
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+}
+
+func helperPlugin(mode string, opts ...PluginOption) Step {
+	opts = append([]PluginOption{
+		WithPluginArgs("-test.run=^TestPluginHelperProcess$"),
+		// The race detector otherwise delays the exit of the helper by a second.
+		WithPluginEnv("GEMARA_TEST_PLUGIN="+mode, "GORACE=atexit_sleep_ms=0"),
+		WithPluginName("plugins/" + mode),
+	}, opts...)
+	return PluginStep(os.Args[0], "OSPS-AC-01.01", opts...)
+}
+
+func TestPluginStep(t *testing.T) {
+	step := helperPlugin("echo", WithPluginParameters(map[string]interface{}{"minimum": 2}))
+	assert.Equal(t, "plugins/echo", step.Name())
+
+	result := step.Run(context.Background(), StepInput{Payload: map[string]interface{}{"mfa": true}})
+	assert.Equal(t, Passed, result.Result)
+	assert.Equal(t, "checked OSPS-AC-01.01", result.Message)
+	assert.Equal(t, High, result.ConfidenceLevel)
+
+	result = step.Run(context.Background(), StepInput{Payload: map[string]interface{}{"mfa": false}})
+	assert.Equal(t, Failed, result.Result)
+}
+
+func TestPluginStep_Evidence(t *testing.T) {
+	step := helperPlugin("echo", WithPluginParameters(map[string]interface{}{"minimum": 2}))
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{step})
+	require.NoError(t, err)
+
+	assert.Equal(t, Passed, assessment.Run(map[string]interface{}{"mfa": true}))
+	require.Len(t, assessment.Evidence, 1)
+	assert.Equal(t, "organization settings", assessment.Evidence[0].Description)
+	assert.Equal(t, "mfa: enabled", assessment.Evidence[0].Content)
+	assert.Equal(t, "plugins/echo", assessment.Evidence[0].Step)
+	assert.NotEmpty(t, assessment.Evidence[0].Collected)
+}
+
+func TestPluginStep_Errors(t *testing.T) {
+	tests := []struct {
+		mode    string
+		message string
+	}{
+		{mode: "crash", message: "plugin plugins/crash failed: exit status 3\nstderr: token expired"},
+		{mode: "garbage", message: "plugin plugins/garbage returned an invalid response: invalid character 'o' in literal null (expecting 'u')"},
+		{mode: "version", message: `plugin plugins/version answered with unsupported protocol version "99", expected "1"`},
+		{mode: "flood", message: "plugin plugins/flood wrote a response larger than 4194304 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			result := helperPlugin(tt.mode).Run(context.Background(), StepInput{})
+			assert.Equal(t, Unknown, result.Result)
+			assert.Equal(t, tt.message, result.Message)
+			assert.Equal(t, Undetermined, result.ConfidenceLevel)
+		})
+	}
+
+	result := PluginStep("./test-data/missing-plugin", "OSPS-AC-01.01").Run(context.Background(), StepInput{})
+	assert.Equal(t, Unknown, result.Result)
+	assert.True(t, strings.HasPrefix(result.Message, "plugin ./test-data/missing-plugin failed: "), result.Message)
+}
+
+func TestPluginStep_Timeout(t *testing.T) {
+	start := time.Now()
+	result := helperPlugin("hang", WithPluginTimeout(100*time.Millisecond)).Run(context.Background(), StepInput{})
+	assert.Equal(t, Unknown, result.Result)
+	assert.Equal(t, "plugin plugins/hang failed: plugin timeout of 100ms exceeded", result.Message)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assessment, err := newAssessment("OSPS-AC-01.01", "description", testingApplicability, StepList{helperPlugin("hang")})
+	require.NoError(t, err)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	assert.Equal(t, Unknown, assessment.RunContext(ctx, nil))
+	assert.Equal(t, "plugins/hang", assessment.StepRecords[0].Name)
+}
@@ -0,0 +1,246 @@
+package gemara
+
+import (
+	"fmt"
+)
+
+const (
+	// ModTypeAdd adds a new assessment requirement, identified by the modifier id, to the target control.
+	ModTypeAdd ModType = "add"
+	// ModTypeModify updates the target assessment requirement with every field set on the modifier.
+	ModTypeModify ModType = "modify"
+	// ModTypeRemove removes the target assessment requirement.
+	ModTypeRemove ModType = "remove"
+	// ModTypeReplace swaps the target assessment requirement for a new one identified by the modifier id.
+	ModTypeReplace ModType = "replace"
+	// ModTypeOverride rewrites the text, applicability and recommendation of the target assessment
+	// requirement in place, clearing any field that is not set on the modifier.
+	ModTypeOverride ModType = "override"
+)
+
+// EffectiveCatalog is the result of tailoring the catalogs imported by a policy.
+type EffectiveCatalog struct {
+	// Catalog contains the controls that remain after exclusions and modifications are applied.
+	Catalog Catalog
+	// ControlSources maps the id of each control to the catalog import of the policy that provides it.
+	ControlSources map[string]SingleMapping
+	// Constraints maps a control or assessment requirement id to the policy constraints targeting it.
+	Constraints map[string][]Constraint
+	// Modifications maps an assessment requirement id to the modifiers that were applied to it.
+	Modifications map[string][]AssessmentRequirementModifier
+}
+
+// ResolveCatalogs fetches every catalog in Imports.Catalogs and applies the policy's exclusions,
+// assessment requirement modifications and constraints to produce a single effective catalog.
+// Each CatalogImport.ReferenceId must match a MappingReference in the policy metadata.
+// Imported catalogs are resolved with Catalog.Resolve using the same fetcher.
+func (p *Policy) ResolveCatalogs(fetch CatalogFetcher) (*EffectiveCatalog, error) {
+	effective := &EffectiveCatalog{
+		Catalog: Catalog{
+			Title: p.Title,
+			Metadata: Metadata{
+				Id:          p.Metadata.Id,
+				Version:     p.Metadata.Version,
+				Date:        p.Metadata.Date,
+				Description: p.Metadata.Description,
+				Author:      p.Metadata.Author,
+			},
+		},
+		ControlSources: make(map[string]SingleMapping),
+		Constraints:    make(map[string][]Constraint),
+		Modifications:  make(map[string][]AssessmentRequirementModifier),
+	}
+
+	for _, catalogImport := range p.Imports.Catalogs {
+		reference, ok := findMappingReference(p.Metadata, catalogImport.ReferenceId)
+		if !ok {
+			return nil, fmt.Errorf("no mapping reference found for catalog import %q", catalogImport.ReferenceId)
+		}
+		source, err := fetch(reference)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching catalog %q: %w", catalogImport.ReferenceId, err)
+		}
+		resolved, err := source.Resolve(fetch)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving catalog %q: %w", catalogImport.ReferenceId, err)
+		}
+
+		tailored := copyControls(resolved.Catalog.Controls)
+		tailored, err = applyExclusions(tailored, catalogImport.Exclusions)
+		if err != nil {
+			return nil, fmt.Errorf("error applying exclusions for catalog %q: %w", catalogImport.ReferenceId, err)
+		}
+		for _, modifier := range catalogImport.AssessmentRequirementModifications {
+			if err := applyModifier(tailored, modifier); err != nil {
+				return nil, fmt.Errorf("error applying modification %q for catalog %q: %w", modifier.Id, catalogImport.ReferenceId, err)
+			}
+			key := modifier.TargetId
+			if modifier.ModificationType == ModTypeAdd || modifier.ModificationType == ModTypeReplace {
+				key = modifier.Id
+			}
+			effective.Modifications[key] = append(effective.Modifications[key], modifier)
+		}
+		for _, constraint := range catalogImport.Constraints {
+			if !hasControlOrRequirement(tailored, constraint.TargetId) {
+				return nil, fmt.Errorf("constraint %q targets %q, which is not in catalog %q", constraint.Id, constraint.TargetId, catalogImport.ReferenceId)
+			}
+			effective.Constraints[constraint.TargetId] = append(effective.Constraints[constraint.TargetId], constraint)
+		}
+
+		if err := effective.merge(reference, resolved, tailored); err != nil {
+			return nil, err
+		}
+	}
+
+	return effective, nil
+}
+
+// merge adds the tailored controls of an imported catalog, along with the families, threats,
+// capabilities and mapping references they depend on.
+func (e *EffectiveCatalog) merge(reference MappingReference, resolved *ResolvedCatalog, controls []Control) error {
+	for _, control := range controls {
+		if _, exists := e.ControlSources[control.Id]; exists {
+			return fmt.Errorf("control %q is imported by more than one catalog", control.Id)
+		}
+		e.ControlSources[control.Id] = SingleMapping{ReferenceId: reference.Id, EntryId: control.Id}
+		e.Catalog.Controls = append(e.Catalog.Controls, control)
+
+		if _, ok := findFamily(&e.Catalog, control.Family); !ok {
+			if family, ok := findFamily(&resolved.Catalog, control.Family); ok {
+				e.Catalog.Families = append(e.Catalog.Families, family)
+			}
+		}
+	}
+	for _, threat := range resolved.Catalog.Threats {
+		if _, ok := findThreat(&e.Catalog, threat.Id); !ok {
+			e.Catalog.Threats = append(e.Catalog.Threats, threat)
+		}
+	}
+	for _, capability := range resolved.Catalog.Capabilities {
+		if _, ok := findCapability(&e.Catalog, capability.Id); !ok {
+			e.Catalog.Capabilities = append(e.Catalog.Capabilities, capability)
+		}
+	}
+
+	references := append([]MappingReference{reference}, resolved.Catalog.Metadata.MappingReferences...)
+	for _, ref := range references {
+		if _, ok := findMappingReference(e.Catalog.Metadata, ref.Id); !ok {
+			e.Catalog.Metadata.MappingReferences = append(e.Catalog.Metadata.MappingReferences, ref)
+		}
+	}
+	return nil
+}
+
+// copyControls returns a copy of controls whose assessment requirements can be modified
+// without affecting the source catalog.
+func copyControls(controls []Control) []Control {
+	copied := make([]Control, len(controls))
+	for i, control := range controls {
+		control.AssessmentRequirements = append([]AssessmentRequirement{}, control.AssessmentRequirements...)
+		copied[i] = control
+	}
+	return copied
+}
+
+// applyExclusions drops every control or assessment requirement whose id is listed in exclusions.
+func applyExclusions(controls []Control, exclusions []string) ([]Control, error) {
+	for _, id := range exclusions {
+		if !hasControlOrRequirement(controls, id) {
+			return nil, fmt.Errorf("excluded id %q is not a control or assessment requirement", id)
+		}
+	}
+	excluded := make(map[string]bool, len(exclusions))
+	for _, id := range exclusions {
+		excluded[id] = true
+	}
+
+	var kept []Control
+	for _, control := range controls {
+		if excluded[control.Id] {
+			continue
+		}
+		var requirements []AssessmentRequirement
+		for _, requirement := range control.AssessmentRequirements {
+			if !excluded[requirement.Id] {
+				requirements = append(requirements, requirement)
+			}
+		}
+		control.AssessmentRequirements = requirements
+		kept = append(kept, control)
+	}
+	return kept, nil
+}
+
+// applyModifier changes the assessment requirements of controls according to the modifier's ModType.
+func applyModifier(controls []Control, modifier AssessmentRequirementModifier) error {
+	if modifier.ModificationType == ModTypeAdd {
+		for i := range controls {
+			if controls[i].Id != modifier.TargetId {
+				continue
+			}
+			if hasControlOrRequirement(controls, modifier.Id) {
+				return fmt.Errorf("assessment requirement %q already exists", modifier.Id)
+			}
+			controls[i].AssessmentRequirements = append(controls[i].AssessmentRequirements, AssessmentRequirement{
+				Id:             modifier.Id,
+				Text:           modifier.Text,
+				Applicability:  modifier.Applicability,
+				Recommendation: modifier.Recommendation,
+			})
+			return nil
+		}
+		return fmt.Errorf("target control %q not found", modifier.TargetId)
+	}
+
+	for i := range controls {
+		requirements := controls[i].AssessmentRequirements
+		for j := range requirements {
+			if requirements[j].Id != modifier.TargetId {
+				continue
+			}
+			switch modifier.ModificationType {
+			case ModTypeModify:
+				if modifier.Text != "" {
+					requirements[j].Text = modifier.Text
+				}
+				if len(modifier.Applicability) > 0 {
+					requirements[j].Applicability = modifier.Applicability
+				}
+				if modifier.Recommendation != "" {
+					requirements[j].Recommendation = modifier.Recommendation
+				}
+			case ModTypeOverride:
+				requirements[j].Text = modifier.Text
+				requirements[j].Applicability = modifier.Applicability
+				requirements[j].Recommendation = modifier.Recommendation
+			case ModTypeReplace:
+				requirements[j] = AssessmentRequirement{
+					Id:             modifier.Id,
+					Text:           modifier.Text,
+					Applicability:  modifier.Applicability,
+					Recommendation: modifier.Recommendation,
+				}
+			case ModTypeRemove:
+				controls[i].AssessmentRequirements = append(requirements[:j:j], requirements[j+1:]...)
+			default:
+				return fmt.Errorf("unsupported modification type %q", modifier.ModificationType)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("target assessment requirement %q not found", modifier.TargetId)
+}
+
+func hasControlOrRequirement(controls []Control, id string) bool {
+	for _, control := range controls {
+		if control.Id == id {
+			return true
+		}
+		for _, requirement := range control.AssessmentRequirements {
+			if requirement.Id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
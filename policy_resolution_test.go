@@ -0,0 +1,167 @@
+package gemara
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_ResolveCatalogs(t *testing.T) {
+	p := &Policy{}
+	require.NoError(t, p.LoadFile("file://test-data/good-osps-policy.yaml"))
+
+	effective, err := p.ResolveCatalogs(NewCatalogFetcher(RelativeFetcher("test-data", DefaultFetcher)))
+	require.NoError(t, err)
+
+	source := &Catalog{}
+	require.NoError(t, source.LoadFile("file://test-data/good-osps.yml"))
+
+	_, excluded := findControl(&effective.Catalog, "OSPS-AC-04")
+	assert.False(t, excluded, "excluded controls should be dropped")
+	assert.Len(t, effective.Catalog.Controls, len(source.Controls)-1)
+	assert.Equal(t, SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01"}, effective.ControlSources["OSPS-AC-01"])
+
+	br01, _ := findControl(&effective.Catalog, "OSPS-BR-01")
+	require.Len(t, br01.AssessmentRequirements, 1, "excluded requirements should be dropped")
+	assert.Equal(t, "OSPS-BR-01.01", br01.AssessmentRequirements[0].Id)
+
+	ac01, _ := findControl(&effective.Catalog, "OSPS-AC-01")
+	assert.Contains(t, ac01.AssessmentRequirements[0].Applicability, "Internal")
+	assert.NotEmpty(t, ac01.AssessmentRequirements[0].Recommendation, "modify should keep fields that are not set")
+
+	ac03, _ := findControl(&effective.Catalog, "OSPS-AC-03")
+	require.Len(t, ac03.AssessmentRequirements, 2)
+	assert.Equal(t, "OSPS-AC-03.01", ac03.AssessmentRequirements[0].Id)
+	assert.Equal(t, "EX-AC-03.03", ac03.AssessmentRequirements[1].Id)
+
+	require.Len(t, effective.Constraints["OSPS-AC-01"], 1)
+	assert.Equal(t, "EX-CON-01", effective.Constraints["OSPS-AC-01"][0].Id)
+	require.Len(t, effective.Constraints["OSPS-AC-03.01"], 1)
+	assert.Len(t, effective.Modifications["OSPS-AC-01.01"], 1)
+	assert.Len(t, effective.Modifications["EX-AC-03.03"], 1)
+
+	sourceAC01, _ := findControl(source, "OSPS-AC-01")
+	assert.NotContains(t, sourceAC01.AssessmentRequirements[0].Applicability, "Internal")
+
+	_, ok := findMappingReference(effective.Catalog.Metadata, "OSPS-B")
+	assert.True(t, ok)
+	_, ok = findMappingReference(effective.Catalog.Metadata, "BPB")
+	assert.True(t, ok, "mapping references used by imported controls should be kept")
+}
+
+func TestPolicy_ResolveCatalogs_Modifiers(t *testing.T) {
+	base := &Catalog{
+		Metadata: Metadata{Id: "BASE"},
+		Families: []Family{{Id: "FAM"}},
+		Controls: []Control{
+			{
+				Id:     "CTRL-1",
+				Family: "FAM",
+				AssessmentRequirements: []AssessmentRequirement{
+					{Id: "CTRL-1.1", Text: "original", Applicability: []string{"a"}, Recommendation: "do it"},
+					{Id: "CTRL-1.2", Text: "second", Applicability: []string{"a"}},
+				},
+			},
+		},
+	}
+	fetch := func(MappingReference) (*Catalog, error) { return base, nil }
+	policy := func(imp CatalogImport) *Policy {
+		imp.ReferenceId = "BASE"
+		return &Policy{
+			Metadata: Metadata{MappingReferences: []MappingReference{{Id: "BASE"}}},
+			Imports:  Imports{Catalogs: []CatalogImport{imp}},
+		}
+	}
+	modify := func(modType ModType, target string, id string, text string) CatalogImport {
+		return CatalogImport{AssessmentRequirementModifications: []AssessmentRequirementModifier{
+			{Id: id, TargetId: target, ModificationType: modType, Text: text},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		imp      CatalogImport
+		wantErr  string
+		wantReqs []AssessmentRequirement
+	}{
+		{
+			name: "Modify keeps unset fields",
+			imp:  modify(ModTypeModify, "CTRL-1.1", "M", "changed"),
+			wantReqs: []AssessmentRequirement{
+				{Id: "CTRL-1.1", Text: "changed", Applicability: []string{"a"}, Recommendation: "do it"},
+				{Id: "CTRL-1.2", Text: "second", Applicability: []string{"a"}},
+			},
+		},
+		{
+			name: "Override clears unset fields",
+			imp:  modify(ModTypeOverride, "CTRL-1.1", "M", "changed"),
+			wantReqs: []AssessmentRequirement{
+				{Id: "CTRL-1.1", Text: "changed"},
+				{Id: "CTRL-1.2", Text: "second", Applicability: []string{"a"}},
+			},
+		},
+		{
+			name: "Replace swaps the requirement id",
+			imp:  modify(ModTypeReplace, "CTRL-1.1", "ORG-1.1", "changed"),
+			wantReqs: []AssessmentRequirement{
+				{Id: "ORG-1.1", Text: "changed"},
+				{Id: "CTRL-1.2", Text: "second", Applicability: []string{"a"}},
+			},
+		},
+		{
+			name: "Remove drops the requirement",
+			imp:  modify(ModTypeRemove, "CTRL-1.1", "M", ""),
+			wantReqs: []AssessmentRequirement{
+				{Id: "CTRL-1.2", Text: "second", Applicability: []string{"a"}},
+			},
+		},
+		{
+			name:    "Add with a duplicate id",
+			imp:     modify(ModTypeAdd, "CTRL-1", "CTRL-1.2", "dup"),
+			wantErr: `assessment requirement "CTRL-1.2" already exists`,
+		},
+		{
+			name:    "Unknown target",
+			imp:     modify(ModTypeModify, "CTRL-9.9", "M", "x"),
+			wantErr: `target assessment requirement "CTRL-9.9" not found`,
+		},
+		{
+			name:    "Unsupported modification type",
+			imp:     modify("clarification", "CTRL-1.1", "M", "x"),
+			wantErr: `unsupported modification type "clarification"`,
+		},
+		{
+			name:    "Unknown exclusion",
+			imp:     CatalogImport{Exclusions: []string{"CTRL-9"}},
+			wantErr: `excluded id "CTRL-9" is not a control or assessment requirement`,
+		},
+		{
+			name:    "Constraint on an excluded control",
+			imp:     CatalogImport{Exclusions: []string{"CTRL-1"}, Constraints: []Constraint{{Id: "C", TargetId: "CTRL-1"}}},
+			wantErr: `constraint "C" targets "CTRL-1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective, err := policy(tt.imp).ResolveCatalogs(fetch)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, effective.Catalog.Controls, 1)
+			assert.Equal(t, tt.wantReqs, effective.Catalog.Controls[0].AssessmentRequirements)
+			assert.Len(t, base.Controls[0].AssessmentRequirements, 2, "the source catalog should not be modified")
+			assert.Equal(t, "original", base.Controls[0].AssessmentRequirements[0].Text)
+		})
+	}
+
+	t.Run("Missing mapping reference", func(t *testing.T) {
+		p := &Policy{Imports: Imports{Catalogs: []CatalogImport{{ReferenceId: "NOPE"}}}}
+		_, err := p.ResolveCatalogs(fetch)
+		assert.ErrorContains(t, err, `no mapping reference found for catalog import "NOPE"`)
+	})
+}
@@ -0,0 +1,191 @@
+package gemara
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
+
+// Position locates a node in a source document.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position refers to a line in a document.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Positions is a side table mapping the path of every field and list item in a document,
+// in the form used by Diagnostic.Path (e.g. "controls[2].assessment-requirements[0]"),
+// to where it is defined in the source. The document root has the path ".".
+// DecodeWithPositions records it while decoding a document, and WithPositions passes it to
+// the Validate methods so that their diagnostics point at the source.
+type Positions map[string]Position
+
+// locate records the position of every field and list item in file, naming the source name.
+func locate(name string, file *ast.File) Positions {
+	positions := make(Positions)
+	for _, doc := range file.Docs {
+		if doc.Body != nil {
+			positions.walk(name, ".", doc.Body)
+		}
+	}
+	return positions
+}
+
+// at returns the path of the field or list item that contains the given line and column,
+// which is the last one defined at or before it, preferring the deepest path.
+func (p Positions) at(line int, column int) string {
+	var found string
+	var foundPosition Position
+	for path, position := range p {
+		if position.Line > line || (position.Line == line && position.Column > column) {
+			continue
+		}
+		after := position.Line > foundPosition.Line ||
+			(position.Line == foundPosition.Line && position.Column > foundPosition.Column)
+		deeper := position.Line == foundPosition.Line && position.Column == foundPosition.Column && len(path) > len(found)
+		if found == "" || after || deeper {
+			found, foundPosition = path, position
+		}
+	}
+	return found
+}
+
+// DecodeError reports where in its source a document could not be decoded.
+type DecodeError struct {
+	// Position is where decoding failed.
+	Position Position
+	// Path is the field or list item being decoded, in the form used by Diagnostic.Path,
+	// or empty when the document could not be parsed.
+	Path string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" || e.Path == "." {
+		return fmt.Sprintf("%s: %v", e.Position, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Position, e.Path, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeError returns err located in the source name, using positions to find the path being
+// decoded, or err itself when it carries no position.
+func decodeError(name string, positions Positions, err error) error {
+	var line, column int
+	var yamlErr yaml.Error
+	var jsonErr *loaders.PositionError
+	switch {
+	case errors.As(err, &yamlErr) && yamlErr.GetToken() != nil && yamlErr.GetToken().Position != nil:
+		line, column = yamlErr.GetToken().Position.Line, yamlErr.GetToken().Position.Column
+	case errors.As(err, &jsonErr):
+		line, column = jsonErr.Line, jsonErr.Column
+	default:
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return &DecodeError{
+		Position: Position{File: name, Line: line, Column: column},
+		Path:     positions.at(line, column),
+		Err:      err,
+	}
+}
+
+// Lookup returns the position of path, or of its closest enclosing field or list item
+// when path itself is not in the table, for instance because the field is missing.
+func (p Positions) Lookup(path string) (Position, bool) {
+	for path != "" {
+		if position, ok := p[path]; ok {
+			return position, true
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut <= 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	position, ok := p["."]
+	return position, ok
+}
+
+// Annotate returns a copy of diagnostics with the Position of each diagnostic set from the table.
+func (p Positions) Annotate(diagnostics Diagnostics) Diagnostics {
+	annotated := make(Diagnostics, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		if position, ok := p.Lookup(diagnostic.Path); ok {
+			diagnostic.Position = position
+		}
+		annotated[i] = diagnostic
+	}
+	return annotated
+}
+
+func (p Positions) walk(file string, path string, node ast.Node) {
+	p.record(file, path, node)
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		for _, value := range n.Values {
+			p.walkMappingValue(file, path, value)
+		}
+	case *ast.MappingValueNode:
+		p.walkMappingValue(file, path, n)
+	case *ast.SequenceNode:
+		for i, value := range n.Values {
+			p.walk(file, fmt.Sprintf("%s[%d]", strings.TrimSuffix(path, "."), i), value)
+		}
+	case *ast.AnchorNode:
+		p.walk(file, path, n.Value)
+	case *ast.TagNode:
+		p.walk(file, path, n.Value)
+	}
+}
+
+func (p Positions) walkMappingValue(file string, path string, value *ast.MappingValueNode) {
+	key := value.Key.GetToken().Value
+	fieldPath := key
+	if path != "." {
+		fieldPath = path + "." + key
+	}
+	// The field is located at its key; nested nodes record their own positions.
+	p.record(file, fieldPath, value.Key)
+	p.walk(file, fieldPath, value.Value)
+}
+
+// record stores the position of node for path unless a position is already known.
+func (p Positions) record(file string, path string, node ast.Node) {
+	if _, ok := p[path]; ok || node == nil {
+		return
+	}
+	// Block mappings are tokenized at the first ':', so point at their first key instead.
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			node = n.Values[0].Key
+		}
+	case *ast.MappingValueNode:
+		node = n.Key
+	}
+	token := node.GetToken()
+	if token == nil || token.Position == nil {
+		return
+	}
+	p[path] = Position{File: file, Line: token.Position.Line, Column: token.Position.Column}
+}
@@ -0,0 +1,115 @@
+package gemara
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWithPositions(t *testing.T) {
+	policy := &Policy{}
+	positions, err := DecodeWithPositions(FromPath("test-data/good-osps-policy.yaml"), policy)
+	require.NoError(t, err)
+	assert.Equal(t, "example-org-osps-policy", policy.Metadata.Id)
+
+	tests := []struct {
+		path string
+		want Position
+	}{
+		{".", Position{File: "test-data/good-osps-policy.yaml", Line: 1, Column: 1}},
+		{"metadata.id", Position{File: "test-data/good-osps-policy.yaml", Line: 2, Column: 3}},
+		{"adherence.assessment-plans", Position{File: "test-data/good-osps-policy.yaml", Line: 70, Column: 3}},
+		{"adherence.assessment-plans[1]", Position{File: "test-data/good-osps-policy.yaml", Line: 78, Column: 7}},
+		{"adherence.assessment-plans[1].requirement-id", Position{File: "test-data/good-osps-policy.yaml", Line: 79, Column: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, positions[tt.path])
+		})
+	}
+}
+
+func TestDecodeWithPositions_JSON(t *testing.T) {
+	data := "{\n  \"title\": \"x\",\n  \"controls\": [\n    {\"id\": \"C-1\"},\n    {\"id\": \"C-2\"}\n  ]\n}"
+	catalog := &Catalog{}
+	positions, err := DecodeWithPositions(FromBytes("catalog.json", []byte(data)), catalog)
+	require.NoError(t, err)
+	assert.Equal(t, Position{File: "catalog.json", Line: 2, Column: 3}, positions["title"])
+	assert.Equal(t, 5, positions["controls[1].id"].Line)
+}
+
+func TestDecode_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		position Position
+		path     string
+		message  string
+	}{
+		{
+			name:     "catalog.yaml",
+			data:     "title: x\nmetadata:\n  id: CAT\ncontrols:\n  - id: C-1\n    assessment-requirements: none\n",
+			position: Position{File: "catalog.yaml", Line: 6, Column: 30},
+			path:     "controls[0].assessment-requirements",
+			message:  "string was used where sequence is expected",
+		},
+		{
+			name:     "catalog.json",
+			data:     "{\n  \"title\": \"x\",\n  \"controls\": [\n    {\"id\": 42}\n  ]\n}",
+			position: Position{File: "catalog.json", Line: 4, Column: 14},
+			path:     "controls[0].id",
+			message:  "cannot unmarshal number",
+		},
+		{
+			name:     "broken.yaml",
+			data:     "title: [x\n",
+			position: Position{File: "broken.yaml", Line: 1, Column: 8},
+			message:  "error decoding YAML",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Decode(FromBytes(tt.name, []byte(tt.data)), &Catalog{})
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, tt.position, decodeErr.Position)
+			assert.Equal(t, tt.path, decodeErr.Path)
+			assert.ErrorContains(t, err, tt.message)
+			assert.True(t, strings.HasPrefix(err.Error(), tt.position.String()), err.Error())
+		})
+	}
+}
+
+func TestValidate_WithPositions(t *testing.T) {
+	data := "title: x\nmetadata:\n  id: CAT\nfamilies:\n  - id: FAM\n    title: Family\n    description: d\ncontrols:\n  - id: C-1\n    family: MISSING\n    title: t\n    objective: o\n"
+	catalog := &Catalog{}
+	positions, err := DecodeWithPositions(FromBytes("catalog.yaml", []byte(data)), catalog)
+	require.NoError(t, err)
+
+	diagnostics := catalog.Validate(WithPositions(positions))
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, CodeUnknownFamily, diagnostics[0].Code)
+	assert.Equal(t, Position{File: "catalog.yaml", Line: 10, Column: 5}, diagnostics[0].Position)
+
+	assert.False(t, catalog.Validate()[0].Position.IsValid(), "diagnostics are not located without positions")
+}
+
+func TestPositions_Annotate(t *testing.T) {
+	positions := Positions{
+		".":           {File: "catalog.yaml", Line: 1, Column: 1},
+		"controls[0]": {File: "catalog.yaml", Line: 4, Column: 5},
+	}
+	diagnostics := positions.Annotate(Diagnostics{
+		{Code: CodeMissingField, Severity: SeverityError, Path: "controls[0].family", Message: "family is required"},
+		{Code: CodeMissingField, Severity: SeverityError, Path: "metadata", Message: "metadata is required"},
+	})
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, Position{File: "catalog.yaml", Line: 4, Column: 5}, diagnostics[0].Position)
+	assert.Equal(t, Position{File: "catalog.yaml", Line: 1, Column: 1}, diagnostics[1].Position)
+	assert.Equal(t, "catalog.yaml:4:5: error: controls[0].family [missing-field] family is required", diagnostics[0].String())
+
+	_, ok := Positions{}.Lookup("controls[0]")
+	assert.False(t, ok)
+}
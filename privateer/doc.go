@@ -0,0 +1,14 @@
+// Package privateer provides conversion functions to transform legacy
+// Privateer (pvtr) plugin output into Gemara Layer 4 evaluation logs.
+//
+// Privateer plugins produced evaluation results before the Layer 4 schema
+// was finalized. Their output uses an `evaluation-set` of control evaluations
+// keyed by `control-id`, with assessment logs keyed by `requirement-id`, and
+// carries fields such as `corrupted-state`, `value` and `changes` that have
+// no equivalent in the current schema.
+//
+// This package maps that legacy shape onto EvaluationLog, ControlEvaluation
+// and AssessmentLog, and reports every populated field that could not be
+// carried over so callers can decide whether the loss is acceptable. Fields
+// that are not part of the legacy format are rejected when a report is loaded.
+package privateer
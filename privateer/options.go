@@ -0,0 +1,62 @@
+package privateer
+
+import (
+	"fmt"
+
+	"github.com/ossf/gemara"
+)
+
+type importOpts struct {
+	referenceId string
+	metadata    *gemara.Metadata
+}
+
+func (i *importOpts) completeFromReport(report Report) {
+	if i.metadata == nil {
+		i.metadata = &gemara.Metadata{
+			Id:          report.PluginName,
+			Description: fmt.Sprintf("Imported from legacy %s output of the %s plugin", report.ServiceName, report.PluginName),
+			Author: gemara.Actor{
+				Id:   report.PluginName,
+				Name: report.PluginName,
+				Type: gemara.Software,
+			},
+		}
+	}
+	if i.referenceId == "" {
+		return
+	}
+	for _, reference := range i.metadata.MappingReferences {
+		if reference.Id == i.referenceId {
+			return
+		}
+	}
+	// Copy the references so that the caller's metadata is left untouched.
+	references := make([]gemara.MappingReference, 0, len(i.metadata.MappingReferences)+1)
+	references = append(references, i.metadata.MappingReferences...)
+	i.metadata.MappingReferences = append(references, gemara.MappingReference{
+		Id:      i.referenceId,
+		Title:   i.referenceId,
+		Version: "unknown",
+	})
+}
+
+// ImportOption defines an option to tune the behavior of ToEvaluationLog.
+type ImportOption func(opts *importOpts)
+
+// WithReferenceId is an ImportOption that sets the mapping reference id used for every
+// control and requirement. Legacy reports do not record which catalog they were evaluated against.
+func WithReferenceId(referenceId string) ImportOption {
+	return func(opts *importOpts) {
+		opts.referenceId = referenceId
+	}
+}
+
+// WithMetadata is an ImportOption that sets the metadata of the resulting EvaluationLog.
+// If unset, metadata is derived from the service and plugin names in the report. Either way,
+// the reference set with WithReferenceId is added to its mapping references when missing.
+func WithMetadata(metadata gemara.Metadata) ImportOption {
+	return func(opts *importOpts) {
+		opts.metadata = &metadata
+	}
+}
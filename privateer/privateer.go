@@ -0,0 +1,164 @@
+package privateer
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/ossf/gemara"
+)
+
+// Report is the top-level document written by legacy Privateer plugins.
+type Report struct {
+	ServiceName   string              `json:"service_name" yaml:"service_name"`
+	PluginName    string              `json:"plugin_name" yaml:"plugin_name"`
+	Payload       interface{}         `json:"payload,omitempty" yaml:"payload,omitempty"`
+	EvaluationSet []ControlEvaluation `json:"evaluation-set" yaml:"evaluation-set"`
+}
+
+// ControlEvaluation is the legacy representation of a single control evaluation.
+type ControlEvaluation struct {
+	Name           string          `json:"name" yaml:"name"`
+	ControlId      string          `json:"control-id" yaml:"control-id"`
+	Result         gemara.Result   `json:"result" yaml:"result"`
+	Message        string          `json:"message" yaml:"message"`
+	CorruptedState bool            `json:"corrupted-state" yaml:"corrupted-state"`
+	AssessmentLogs []AssessmentLog `json:"assessment-logs" yaml:"assessment-logs"`
+}
+
+// AssessmentLog is the legacy representation of a single assessment.
+type AssessmentLog struct {
+	RequirementId  string                 `json:"requirement-id" yaml:"requirement-id"`
+	Applicability  []string               `json:"applicability" yaml:"applicability"`
+	Description    string                 `json:"description" yaml:"description"`
+	Result         gemara.Result          `json:"result" yaml:"result"`
+	Message        string                 `json:"message" yaml:"message"`
+	Steps          gemara.StepList        `json:"steps" yaml:"steps"`
+	StepsExecuted  int64                  `json:"steps-executed,omitempty" yaml:"steps-executed,omitempty"`
+	Start          gemara.Datetime        `json:"start" yaml:"start"`
+	End            gemara.Datetime        `json:"end,omitempty" yaml:"end,omitempty"`
+	Value          interface{}            `json:"value,omitempty" yaml:"value,omitempty"`
+	Changes        map[string]interface{} `json:"changes,omitempty" yaml:"changes,omitempty"`
+	Recommendation string                 `json:"recommendation,omitempty" yaml:"recommendation,omitempty"`
+}
+
+// DroppedField describes a populated legacy field that has no equivalent in the Layer 4 schema.
+type DroppedField struct {
+	// Path locates the field in the legacy document, e.g. "evaluation-set[2].assessment-logs[0].changes".
+	Path string
+	// Reason explains why the field could not be carried over.
+	Reason string
+}
+
+func (d DroppedField) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Reason)
+}
+
+// LoadFile loads a legacy Privateer report from a YAML or JSON file at the provided path.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+// Fields that are not part of the legacy format are rejected, so that a migration cannot lose
+// them unnoticed.
+func (r *Report) LoadFile(sourcePath string) error {
+	format, err := gemara.FormatFromPath(sourcePath)
+	if err != nil {
+		return err
+	}
+	source := gemara.FromURI(sourcePath, gemara.DefaultFetcher)
+	if format == gemara.FormatJSON {
+		// JSON documents are always decoded strictly.
+		return gemara.Decode(source, r)
+	}
+	reader, err := source.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	if err := yaml.NewDecoder(reader, yaml.DisallowUnknownField()).Decode(r); err != nil {
+		return fmt.Errorf("error decoding YAML: %w", err)
+	}
+	return nil
+}
+
+// ToEvaluationLog converts a legacy Privateer report into a Layer 4 EvaluationLog.
+// The returned DroppedFields list every populated legacy field that was not carried over.
+func ToEvaluationLog(report Report, opts ...ImportOption) (gemara.EvaluationLog, []DroppedField) {
+	options := importOpts{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	options.completeFromReport(report)
+
+	var dropped []DroppedField
+	if report.Payload != nil {
+		dropped = append(dropped, DroppedField{
+			Path:   "payload",
+			Reason: "target payloads are not recorded in evaluation logs",
+		})
+	}
+
+	evaluationLog := gemara.EvaluationLog{
+		Metadata:    *options.metadata,
+		Evaluations: []*gemara.ControlEvaluation{},
+	}
+
+	for i, legacy := range report.EvaluationSet {
+		evaluationPath := fmt.Sprintf("evaluation-set[%d]", i)
+		if legacy.CorruptedState {
+			dropped = append(dropped, DroppedField{
+				Path:   evaluationPath + ".corrupted-state",
+				Reason: "target corruption tracking is not part of the Layer 4 schema",
+			})
+		}
+
+		name := legacy.Name
+		if name == "" {
+			name = legacy.ControlId
+		}
+		evaluation := &gemara.ControlEvaluation{
+			Name:    name,
+			Result:  legacy.Result,
+			Message: legacy.Message,
+			Control: gemara.SingleMapping{
+				ReferenceId: options.referenceId,
+				EntryId:     legacy.ControlId,
+			},
+			AssessmentLogs: []*gemara.AssessmentLog{},
+		}
+
+		for j, legacyLog := range legacy.AssessmentLogs {
+			logPath := fmt.Sprintf("%s.assessment-logs[%d]", evaluationPath, j)
+			if legacyLog.Value != nil {
+				dropped = append(dropped, DroppedField{
+					Path:   logPath + ".value",
+					Reason: "step return values are not part of the Layer 4 schema",
+				})
+			}
+			if len(legacyLog.Changes) > 0 {
+				dropped = append(dropped, DroppedField{
+					Path:   logPath + ".changes",
+					Reason: "change tracking is not part of the Layer 4 schema",
+				})
+			}
+
+			evaluation.AssessmentLogs = append(evaluation.AssessmentLogs, &gemara.AssessmentLog{
+				Requirement: gemara.SingleMapping{
+					ReferenceId: options.referenceId,
+					EntryId:     legacyLog.RequirementId,
+				},
+				Description:    legacyLog.Description,
+				Result:         legacyLog.Result,
+				Message:        legacyLog.Message,
+				Applicability:  legacyLog.Applicability,
+				Steps:          legacyLog.Steps,
+				StepsExecuted:  legacyLog.StepsExecuted,
+				Start:          legacyLog.Start,
+				End:            legacyLog.End,
+				Recommendation: legacyLog.Recommendation,
+			})
+		}
+
+		evaluationLog.Evaluations = append(evaluationLog.Evaluations, evaluation)
+	}
+
+	return evaluationLog, dropped
+}
@@ -0,0 +1,151 @@
+package privateer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ossf/gemara"
+)
+
+func TestReport_LoadFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourcePath string
+		wantErr    bool
+	}{
+		{
+			name:       "Bad path",
+			sourcePath: "file://bad-path.yaml",
+			wantErr:    true,
+		},
+		{
+			name:       "Unsupported file extension",
+			sourcePath: "file://../test-data/unsupported.txt",
+			wantErr:    true,
+		},
+		{
+			name:       "Good YAML — pvtr baseline scan",
+			sourcePath: "file://../test-data/pvtr-baseline-scan.yaml",
+			wantErr:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Report{}
+			err := r.LoadFile(tt.sourcePath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "pvtr", r.ServiceName)
+			assert.Equal(t, "github-repo", r.PluginName)
+			assert.Len(t, r.EvaluationSet, 39)
+		})
+	}
+}
+
+func TestReport_LoadFile_UnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	documents := map[string]string{
+		"report.yaml": "service_name: pvtr\nplugin_name: example\nevaluation-set:\n" +
+			"  - control-id: CTRL-1\n    assessment-logs:\n      - requirement-id: CTRL-1.1\n        remediation: enable MFA\n",
+		"report.json": `{"service_name": "pvtr", "plugin_name": "example", "evaluation-set": [` +
+			`{"control-id": "CTRL-1", "assessment-logs": [{"requirement-id": "CTRL-1.1", "remediation": "enable MFA"}]}]}`,
+	}
+	for name, content := range documents {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+			err := (&Report{}).LoadFile("file://" + path)
+			assert.ErrorContains(t, err, "remediation")
+		})
+	}
+}
+
+func TestToEvaluationLog(t *testing.T) {
+	report := &Report{}
+	require.NoError(t, report.LoadFile("file://../test-data/pvtr-baseline-scan.yaml"))
+
+	log, dropped := ToEvaluationLog(*report, WithReferenceId("OSPS-B"))
+
+	assert.Equal(t, "github-repo", log.Metadata.Id)
+	assert.Equal(t, gemara.Software, log.Metadata.Author.Type)
+	require.Len(t, log.Metadata.MappingReferences, 1)
+	assert.Equal(t, "OSPS-B", log.Metadata.MappingReferences[0].Id)
+	require.Len(t, log.Evaluations, 39)
+
+	var assessmentCount int
+	for _, evaluation := range log.Evaluations {
+		assessmentCount += len(evaluation.AssessmentLogs)
+	}
+	assert.Equal(t, 54, assessmentCount)
+
+	first := log.Evaluations[0]
+	assert.Equal(t, "OSPS-AC-01", first.Name, "empty legacy names should fall back to the control id")
+	assert.Equal(t, gemara.SingleMapping{ReferenceId: "OSPS-B", EntryId: "OSPS-AC-01"}, first.Control)
+	assert.Equal(t, gemara.Passed, first.Result)
+
+	assessment := first.AssessmentLogs[0]
+	assert.Equal(t, "OSPS-AC-01.01", assessment.Requirement.EntryId)
+	assert.Equal(t, "OSPS-B", assessment.Requirement.ReferenceId)
+	assert.Equal(t, int64(1), assessment.StepsExecuted)
+	require.Len(t, assessment.Steps, 1)
+	assert.Equal(t, "github.com/revanite-io/pvtr-github-repo/evaluation_plans/osps/access_control.orgRequiresMFA", assessment.Steps[0].Name())
+	assert.NotEmpty(t, assessment.Start)
+
+	require.Len(t, dropped, 1, "only the payload carries data that cannot be imported")
+	assert.Equal(t, "payload", dropped[0].Path)
+}
+
+func TestToEvaluationLog_DroppedFields(t *testing.T) {
+	report := Report{
+		ServiceName: "pvtr",
+		PluginName:  "example",
+		EvaluationSet: []ControlEvaluation{
+			{
+				ControlId:      "CTRL-1",
+				CorruptedState: true,
+				AssessmentLogs: []AssessmentLog{
+					{RequirementId: "CTRL-1.1"},
+					{
+						RequirementId: "CTRL-1.2",
+						Value:         "token-present",
+						Changes:       map[string]interface{}{"branch-protection": "enabled"},
+					},
+				},
+			},
+		},
+	}
+
+	meta := gemara.Metadata{Id: "custom", Description: "custom metadata"}
+	log, dropped := ToEvaluationLog(report, WithMetadata(meta))
+
+	assert.Equal(t, meta, log.Metadata)
+	assert.Empty(t, log.Evaluations[0].Control.ReferenceId)
+
+	var paths []string
+	for _, d := range dropped {
+		paths = append(paths, d.Path)
+	}
+	assert.Equal(t, []string{
+		"evaluation-set[0].corrupted-state",
+		"evaluation-set[0].assessment-logs[1].value",
+		"evaluation-set[0].assessment-logs[1].changes",
+	}, paths)
+
+	log, _ = ToEvaluationLog(report, WithMetadata(meta), WithReferenceId("OSPS-B"))
+	assert.Equal(t, "custom", log.Metadata.Id)
+	require.Len(t, log.Metadata.MappingReferences, 1)
+	assert.Equal(t, "OSPS-B", log.Metadata.MappingReferences[0].Id)
+	assert.Equal(t, "OSPS-B", log.Evaluations[0].Control.ReferenceId)
+	assert.Empty(t, meta.MappingReferences)
+	for _, diagnostic := range log.Validate() {
+		assert.NotEqual(t, gemara.CodeUnknownMappingReference, diagnostic.Code, diagnostic.String())
+	}
+}
@@ -0,0 +1,530 @@
+package gemara
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProcedureSet is a collection of declarative assessment procedures, which check the target
+// data with assertions instead of compiled code, so they can be shipped alongside a Catalog.
+// In YAML it is written as:
+//
+//	procedures:
+//	  - id: two-reviewers
+//	    requirement-id: OSPS-AC-03.01
+//	    description: Pull requests need two approving reviews
+//	    assertions:
+//	      - path: $.branch-protection.required-reviewers
+//	        minimum: 2
+//	      - path: $.branch-protection.enforce-admins
+//	        equals: true
+//
+// A condition can also be taken from a parameter of the assessment plan:
+//
+//	assertions:
+//	  - path: $.branch-protection.required-reviewers
+//	    parameters:
+//	      minimum: minimum-reviewers
+type ProcedureSet struct {
+	Metadata Metadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Procedures []Procedure `json:"procedures" yaml:"procedures"`
+}
+
+// Procedure is a declarative assessment step. It passes when every assertion holds for the
+// target data. The values the assertions check are returned as inline evidence described by
+// Description.
+type Procedure struct {
+	// Id names the step, as Step.Name does for compiled steps.
+	Id string `json:"id" yaml:"id"`
+
+	// RequirementId is the id of the assessment requirement the procedure assesses.
+	RequirementId string `json:"requirement-id" yaml:"requirement-id"`
+
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	Assertions []Assertion `json:"assertions" yaml:"assertions"`
+
+	// ConfidenceLevel is reported with the result of the procedure. It defaults to High.
+	ConfidenceLevel ConfidenceLevel `json:"confidence-level,omitempty" yaml:"confidence-level,omitempty"`
+}
+
+// Assertion checks the value found at Path in the target data. Every condition that is set
+// must hold. The target data is compared in its JSON form, so numbers are compared by value
+// and structs are addressed by their JSON field names.
+type Assertion struct {
+	// Path locates the value, as in $.repository.branches[0].name or $.labels['app.kubernetes.io/name'].
+	// The leading $ is optional.
+	Path string `json:"path" yaml:"path"`
+
+	// Exists requires the value to be present, or absent when false. Without it, a missing value
+	// makes the procedure report Unknown.
+	Exists *bool `json:"exists,omitempty" yaml:"exists,omitempty"`
+
+	// Equals requires the value to equal the given scalar, list or mapping.
+	Equals interface{} `json:"equals,omitempty" yaml:"equals,omitempty"`
+
+	// NotEquals requires the value to differ from the given scalar, list or mapping.
+	NotEquals interface{} `json:"not-equals,omitempty" yaml:"not-equals,omitempty"`
+
+	// Matches requires the value to be a string matching the regular expression.
+	Matches string `json:"matches,omitempty" yaml:"matches,omitempty"`
+
+	// Minimum requires the value to be a number no lower than the given one.
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+
+	// Maximum requires the value to be a number no greater than the given one.
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+
+	// In requires the value to equal one of the listed values.
+	In []interface{} `json:"in,omitempty" yaml:"in,omitempty"`
+
+	// Contains requires the value to be a list containing the given value, or a string containing the given substring.
+	Contains interface{} `json:"contains,omitempty" yaml:"contains,omitempty"`
+
+	// Parameters takes conditions from the bound parameters of the assessment, mapping a condition
+	// such as minimum to the id of the parameter that supplies its value. A parameter value is read
+	// as JSON when it is valid JSON, such as 2, true or ["1.2", "1.3"], and as a string otherwise.
+	// An assertion whose parameter is not bound makes the procedure report Unknown.
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// LoadFile loads the procedures from a YAML or JSON file at the provided path.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+func (s *ProcedureSet) LoadFile(sourcePath string) error {
+	return loadFile(sourcePath, s)
+}
+
+// Register compiles every procedure, registers it in r under its id and assigns it to its
+// requirement after any steps already assigned there. Nothing is registered if a procedure
+// does not compile.
+func (s *ProcedureSet) Register(r *StepRegistry) error {
+	steps := make([]Step, len(s.Procedures))
+	for i, procedure := range s.Procedures {
+		step, err := procedure.Compile()
+		if err != nil {
+			return err
+		}
+		steps[i] = step
+	}
+	for i, procedure := range s.Procedures {
+		r.RegisterAs(procedure.Id, steps[i])
+		if procedure.RequirementId != "" {
+			r.appendAssignment(procedure.RequirementId, procedure.Id)
+		}
+	}
+	return nil
+}
+
+// Validate checks the procedures for problems that would prevent them from running, such as
+// duplicate ids and assertions that do not compile. When catalogs are provided with WithCatalogs,
+// requirement ids are checked against them.
+func (s *ProcedureSet) Validate(opts ...ValidateOption) Diagnostics {
+	v := newValidator(s.Metadata, opts)
+	controls := v.catalogControls()
+	haveCatalogs := len(v.opts.catalogs) > 0
+
+	ids := make(map[string]string)
+	for i, procedure := range s.Procedures {
+		path := fmt.Sprintf("procedures[%d]", i)
+		if procedure.Id == "" {
+			v.report(SeverityError, CodeMissingField, path+".id", "procedure id is empty")
+		}
+		v.unique(ids, procedure.Id, path+".id")
+		if procedure.RequirementId == "" {
+			v.report(SeverityError, CodeMissingField, path+".requirement-id", "procedure %q is not assigned to a requirement", procedure.Id)
+		} else if haveCatalogs && !hasRequirement(controls, procedure.RequirementId) {
+			v.report(SeverityError, CodeUnknownRequirement, path+".requirement-id",
+				"assessment requirement %q is not defined in the provided catalogs", procedure.RequirementId)
+		}
+		if len(procedure.Assertions) == 0 {
+			v.report(SeverityError, CodeMissingField, path+".assertions", "procedure %q has no assertions", procedure.Id)
+		}
+		for j, assertion := range procedure.Assertions {
+			if _, err := assertion.compile(); err != nil {
+				v.report(SeverityError, CodeInvalidAssertion, fmt.Sprintf("%s.assertions[%d]", path, j), "%v", err)
+			}
+		}
+	}
+	return v.diagnostics
+}
+
+// Compile turns the procedure into a Step named after its id.
+func (p Procedure) Compile() (Step, error) {
+	if p.Id == "" {
+		return nil, fmt.Errorf("procedure has no id")
+	}
+	if len(p.Assertions) == 0 {
+		return nil, fmt.Errorf("procedure %s has no assertions", p.Id)
+	}
+	checks := make([]compiledAssertion, len(p.Assertions))
+	for i, assertion := range p.Assertions {
+		check, err := assertion.compile()
+		if err != nil {
+			return nil, fmt.Errorf("procedure %s: assertions[%d]: %w", p.Id, i, err)
+		}
+		checks[i] = check
+	}
+	confidence := p.ConfidenceLevel
+	if confidence == NotSet {
+		confidence = High
+	}
+
+	description := p.Description
+	if description == "" {
+		description = fmt.Sprintf("values checked by procedure %s", p.Id)
+	}
+
+	return &procedureStep{id: p.Id, description: description, checks: checks, confidence: confidence}, nil
+}
+
+// procedureStep is a compiled Procedure.
+type procedureStep struct {
+	id          string
+	description string
+	checks      []compiledAssertion
+	confidence  ConfidenceLevel
+}
+
+func (s *procedureStep) Name() string { return s.id }
+
+func (s *procedureStep) Run(_ context.Context, input StepInput) StepResult {
+	data, err := normalizeValue(input.Payload)
+	if err != nil {
+		return StepResult{
+			Result:          Unknown,
+			Message:         fmt.Sprintf("target data cannot be represented as JSON: %v", err),
+			ConfidenceLevel: Undetermined,
+		}
+	}
+	for _, check := range s.checks {
+		check, err := check.bind(input.Parameters)
+		if err != nil {
+			return StepResult{Result: Unknown, Message: err.Error(), ConfidenceLevel: Undetermined}
+		}
+		result, message := check.evaluate(data)
+		if result != Passed {
+			return StepResult{Result: result, Message: message, ConfidenceLevel: s.confidence, Evidence: s.evidence(data)}
+		}
+	}
+	return StepResult{
+		Result:          Passed,
+		Message:         fmt.Sprintf("all %d assertions passed", len(s.checks)),
+		ConfidenceLevel: s.confidence,
+		Evidence:        s.evidence(data),
+	}
+}
+
+// evidence records the values found at the paths of the assertions, keyed by path, so the
+// result can be audited. Paths without a value are left out.
+func (s *procedureStep) evidence(data interface{}) []Evidence {
+	values := make(map[string]interface{}, len(s.checks))
+	for _, check := range s.checks {
+		if value, found := lookupValue(data, check.path); found {
+			values[check.Path] = value
+		}
+	}
+	return []Evidence{InlineEvidence(s.description, describeValue(values))}
+}
+
+type compiledAssertion struct {
+	Assertion
+	path    []pathSegment
+	pattern *regexp.Regexp
+}
+
+func (a Assertion) compile() (compiledAssertion, error) {
+	segments, err := parseValuePath(a.Path)
+	if err != nil {
+		return compiledAssertion{}, err
+	}
+	check := compiledAssertion{Assertion: a, path: segments}
+	if check.Equals, err = normalizeValue(a.Equals); err != nil {
+		return compiledAssertion{}, fmt.Errorf("equals: %w", err)
+	}
+	if check.NotEquals, err = normalizeValue(a.NotEquals); err != nil {
+		return compiledAssertion{}, fmt.Errorf("not-equals: %w", err)
+	}
+	if check.Contains, err = normalizeValue(a.Contains); err != nil {
+		return compiledAssertion{}, fmt.Errorf("contains: %w", err)
+	}
+	if a.In != nil {
+		check.In = make([]interface{}, len(a.In))
+		for i, value := range a.In {
+			if check.In[i], err = normalizeValue(value); err != nil {
+				return compiledAssertion{}, fmt.Errorf("in[%d]: %w", i, err)
+			}
+		}
+	}
+	if a.Matches != "" {
+		if check.pattern, err = regexp.Compile(a.Matches); err != nil {
+			return compiledAssertion{}, fmt.Errorf("matches: %w", err)
+		}
+	}
+	for condition, id := range a.Parameters {
+		if !parameterConditions[condition] {
+			return compiledAssertion{}, fmt.Errorf("parameters: %q is not a condition", condition)
+		}
+		if id == "" {
+			return compiledAssertion{}, fmt.Errorf("parameters: %s has no parameter id", condition)
+		}
+	}
+	if a.Exists == nil && a.Equals == nil && a.NotEquals == nil && a.Matches == "" &&
+		a.Minimum == nil && a.Maximum == nil && a.In == nil && a.Contains == nil && len(a.Parameters) == 0 {
+		return compiledAssertion{}, fmt.Errorf("assertion on %s has no conditions", a.Path)
+	}
+	return check, nil
+}
+
+var parameterConditions = map[string]bool{
+	"exists": true, "equals": true, "not-equals": true, "matches": true,
+	"minimum": true, "maximum": true, "in": true, "contains": true,
+}
+
+// bind sets the conditions taken from parameters, replacing any value the assertion gives them.
+func (c compiledAssertion) bind(parameters map[string]string) (compiledAssertion, error) {
+	conditions := make([]string, 0, len(c.Parameters))
+	for condition := range c.Parameters {
+		conditions = append(conditions, condition)
+	}
+	sort.Strings(conditions)
+	for _, condition := range conditions {
+		id := c.Parameters[condition]
+		raw, ok := parameters[id]
+		if !ok {
+			return c, fmt.Errorf("%s: parameter %s is not bound", c.Path, id)
+		}
+		value, err := normalizeValue([]byte(raw))
+		if err != nil {
+			value = raw
+		}
+		invalid := fmt.Errorf("%s: parameter %s is not a valid %s value: %q", c.Path, id, condition, raw)
+		switch condition {
+		case "exists":
+			exists, ok := value.(bool)
+			if !ok {
+				return c, invalid
+			}
+			c.Exists = &exists
+		case "equals":
+			c.Equals = value
+		case "not-equals":
+			c.NotEquals = value
+		case "matches":
+			if c.pattern, err = regexp.Compile(raw); err != nil {
+				return c, invalid
+			}
+			c.Matches = raw
+		case "minimum", "maximum":
+			n, ok := value.(float64)
+			if !ok {
+				return c, invalid
+			}
+			if condition == "minimum" {
+				c.Minimum = &n
+			} else {
+				c.Maximum = &n
+			}
+		case "in":
+			list, ok := value.([]interface{})
+			if !ok {
+				return c, invalid
+			}
+			c.In = list
+		case "contains":
+			c.Contains = value
+		}
+	}
+	return c, nil
+}
+
+func (c compiledAssertion) evaluate(data interface{}) (Result, string) {
+	value, found := lookupValue(data, c.path)
+	if c.Exists != nil && *c.Exists != found {
+		if found {
+			return Failed, fmt.Sprintf("%s: expected no value, got %s", c.Path, describeValue(value))
+		}
+		return Failed, fmt.Sprintf("%s: expected a value, found none", c.Path)
+	}
+	if !found {
+		if c.Exists != nil {
+			return Passed, ""
+		}
+		return Unknown, fmt.Sprintf("%s: not found in target data", c.Path)
+	}
+
+	if c.Equals != nil && !reflect.DeepEqual(value, c.Equals) {
+		return Failed, fmt.Sprintf("%s: expected %s, got %s", c.Path, describeValue(c.Equals), describeValue(value))
+	}
+	if c.NotEquals != nil && reflect.DeepEqual(value, c.NotEquals) {
+		return Failed, fmt.Sprintf("%s: expected a value other than %s", c.Path, describeValue(value))
+	}
+	if c.pattern != nil {
+		s, ok := value.(string)
+		if !ok {
+			return Failed, fmt.Sprintf("%s: expected a string matching %q, got %s", c.Path, c.Matches, describeValue(value))
+		}
+		if !c.pattern.MatchString(s) {
+			return Failed, fmt.Sprintf("%s: %q does not match %q", c.Path, s, c.Matches)
+		}
+	}
+	if c.Minimum != nil || c.Maximum != nil {
+		n, ok := value.(float64)
+		if !ok {
+			return Failed, fmt.Sprintf("%s: expected a number, got %s", c.Path, describeValue(value))
+		}
+		if c.Minimum != nil && n < *c.Minimum {
+			return Failed, fmt.Sprintf("%s: expected at least %v, got %v", c.Path, *c.Minimum, n)
+		}
+		if c.Maximum != nil && n > *c.Maximum {
+			return Failed, fmt.Sprintf("%s: expected at most %v, got %v", c.Path, *c.Maximum, n)
+		}
+	}
+	if c.In != nil && !containsValue(c.In, value) {
+		return Failed, fmt.Sprintf("%s: expected one of %s, got %s", c.Path, describeValue(c.In), describeValue(value))
+	}
+	if c.Contains != nil {
+		switch v := value.(type) {
+		case []interface{}:
+			if !containsValue(v, c.Contains) {
+				return Failed, fmt.Sprintf("%s: expected a list containing %s, got %s", c.Path, describeValue(c.Contains), describeValue(value))
+			}
+		case string:
+			sub, ok := c.Contains.(string)
+			if !ok || !strings.Contains(v, sub) {
+				return Failed, fmt.Sprintf("%s: expected a string containing %s, got %s", c.Path, describeValue(c.Contains), describeValue(value))
+			}
+		default:
+			return Failed, fmt.Sprintf("%s: expected a list or string, got %s", c.Path, describeValue(value))
+		}
+	}
+	return Passed, ""
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func describeValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// normalizeValue converts value to the form encoding/json decodes into an interface{}, so
+// that target data and expected values can be compared regardless of their Go types.
+// Raw JSON is decoded rather than re-encoded.
+func normalizeValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// pathSegment is a field name or, when index is not negative, a list index.
+type pathSegment struct {
+	key   string
+	index int
+}
+
+// parseValuePath parses a path of field names and list indexes such as $.a.b[0]['c.d'].
+func parseValuePath(valuePath string) ([]pathSegment, error) {
+	rest := strings.TrimPrefix(valuePath, "$")
+	if rest == "" {
+		if valuePath == "" {
+			return nil, fmt.Errorf("path is empty")
+		}
+		return nil, nil
+	}
+	if rest == valuePath && rest[0] != '[' {
+		// A path without the leading $ starts with a field name.
+		rest = "." + rest
+	}
+
+	var segments []pathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			key := rest[1:end]
+			if key == "" {
+				return nil, fmt.Errorf("invalid path %q: empty field name", valuePath)
+			}
+			segments = append(segments, pathSegment{key: key, index: -1})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", valuePath)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1], index: -1})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid path %q: %q is not a list index or quoted field name", valuePath, inner)
+				}
+				segments = append(segments, pathSegment{index: index})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", valuePath, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// lookupValue follows path through normalized data and reports whether a value was found.
+func lookupValue(data interface{}, path []pathSegment) (interface{}, bool) {
+	for _, segment := range path {
+		if segment.index >= 0 {
+			list, ok := data.([]interface{})
+			if !ok || segment.index >= len(list) {
+				return nil, false
+			}
+			data = list[segment.index]
+			continue
+		}
+		fields, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if data, ok = fields[segment.key]; !ok {
+			return nil, false
+		}
+	}
+	return data, true
+}
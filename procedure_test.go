@@ -0,0 +1,246 @@
+package gemara
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storageTarget struct {
+	Endpoint    storageEndpoint        `json:"endpoint"`
+	Replication map[string]interface{} `json:"replication"`
+}
+
+type storageEndpoint struct {
+	Protocol string            `json:"protocol"`
+	Hostname string            `json:"hostname"`
+	TLS      map[string]string `json:"tls,omitempty"`
+}
+
+func goodStorageTarget() storageTarget {
+	return storageTarget{
+		Endpoint: storageEndpoint{
+			Protocol: "https",
+			Hostname: "bucket.example.com",
+			TLS:      map[string]string{"min-version": "1.2"},
+		},
+		Replication: map[string]interface{}{
+			"regions": []string{"eu-west-1", "us-east-1"},
+			"copies":  3,
+		},
+	}
+}
+
+func loadProcedures(t *testing.T) *ProcedureSet {
+	t.Helper()
+	procedures := &ProcedureSet{}
+	require.NoError(t, procedures.LoadFile("file://test-data/good-procedures.yaml"))
+	return procedures
+}
+
+func TestProcedure_Compile(t *testing.T) {
+	procedures := loadProcedures(t)
+	require.Len(t, procedures.Procedures, 2)
+	assert.Equal(t, Medium, procedures.Procedures[1].ConfidenceLevel)
+
+	transit, err := procedures.Procedures[0].Compile()
+	require.NoError(t, err)
+	replication, err := procedures.Procedures[1].Compile()
+	require.NoError(t, err)
+	assert.Equal(t, "ccc.encryption-in-transit", transit.Name())
+
+	tests := []struct {
+		name       string
+		step       Step
+		modify     func(*storageTarget)
+		result     Result
+		message    string
+		confidence ConfidenceLevel
+	}{
+		{
+			name:       "all assertions pass",
+			step:       transit,
+			result:     Passed,
+			message:    "all 3 assertions passed",
+			confidence: High,
+		},
+		{
+			name:       "equality",
+			step:       transit,
+			modify:     func(target *storageTarget) { target.Endpoint.Protocol = "http" },
+			result:     Failed,
+			message:    `$.endpoint.protocol: expected "https", got "http"`,
+			confidence: High,
+		},
+		{
+			name:       "membership",
+			step:       transit,
+			modify:     func(target *storageTarget) { target.Endpoint.TLS["min-version"] = "1.0" },
+			result:     Failed,
+			message:    `$.endpoint.tls.min-version: expected one of ["1.2","1.3"], got "1.0"`,
+			confidence: High,
+		},
+		{
+			name:       "regular expression",
+			step:       transit,
+			modify:     func(target *storageTarget) { target.Endpoint.Hostname = "bucket.example.org" },
+			result:     Failed,
+			message:    `$.endpoint.hostname: "bucket.example.org" does not match "\\.example\\.com$"`,
+			confidence: High,
+		},
+		{
+			name:       "missing value",
+			step:       transit,
+			modify:     func(target *storageTarget) { target.Endpoint.TLS = nil },
+			result:     Unknown,
+			message:    "$.endpoint.tls.min-version: not found in target data",
+			confidence: High,
+		},
+		{
+			name:       "list contains",
+			step:       replication,
+			result:     Passed,
+			message:    "all 3 assertions passed",
+			confidence: Medium,
+		},
+		{
+			name:       "numeric threshold",
+			step:       replication,
+			modify:     func(target *storageTarget) { target.Replication["copies"] = 1 },
+			result:     Failed,
+			message:    "$.replication.copies: expected at least 2, got 1",
+			confidence: Medium,
+		},
+		{
+			name:       "list does not contain",
+			step:       replication,
+			modify:     func(target *storageTarget) { target.Replication["regions"] = []string{"eu-west-1"} },
+			result:     Failed,
+			message:    `$.replication.regions: expected a list containing "us-east-1", got ["eu-west-1"]`,
+			confidence: Medium,
+		},
+		{
+			name:       "unexpected value",
+			step:       replication,
+			modify:     func(target *storageTarget) { target.Replication["legacy.mode"] = true },
+			result:     Failed,
+			message:    "$.replication['legacy.mode']: expected no value, got true",
+			confidence: Medium,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := goodStorageTarget()
+			if tt.modify != nil {
+				tt.modify(&target)
+			}
+			result := tt.step.Run(context.Background(), StepInput{Payload: target})
+			assert.Equal(t, tt.result, result.Result)
+			assert.Equal(t, tt.message, result.Message)
+			assert.Equal(t, tt.confidence, result.ConfidenceLevel)
+		})
+	}
+}
+
+func TestProcedure_CompileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		procedure Procedure
+		err       string
+	}{
+		{
+			name:      "no id",
+			procedure: Procedure{Assertions: []Assertion{{Path: "a", Equals: 1}}},
+			err:       "procedure has no id",
+		},
+		{
+			name:      "no assertions",
+			procedure: Procedure{Id: "p"},
+			err:       "procedure p has no assertions",
+		},
+		{
+			name:      "no conditions",
+			procedure: Procedure{Id: "p", Assertions: []Assertion{{Path: "a"}}},
+			err:       "procedure p: assertions[0]: assertion on a has no conditions",
+		},
+		{
+			name:      "invalid path",
+			procedure: Procedure{Id: "p", Assertions: []Assertion{{Path: "$.a[x]", Equals: 1}}},
+			err:       `procedure p: assertions[0]: invalid path "$.a[x]": "x" is not a list index or quoted field name`,
+		},
+		{
+			name:      "invalid pattern",
+			procedure: Procedure{Id: "p", Assertions: []Assertion{{Path: "a", Matches: "("}}},
+			err:       "procedure p: assertions[0]: matches: error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.procedure.Compile()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseValuePath(t *testing.T) {
+	data := map[string]interface{}{
+		"a": []interface{}{map[string]interface{}{"b.c": "value"}},
+	}
+	for _, valuePath := range []string{"$.a[0]['b.c']", `a[0]["b.c"]`} {
+		segments, err := parseValuePath(valuePath)
+		require.NoError(t, err, valuePath)
+		value, found := lookupValue(data, segments)
+		assert.True(t, found, valuePath)
+		assert.Equal(t, "value", value, valuePath)
+	}
+
+	segments, err := parseValuePath("$")
+	require.NoError(t, err)
+	value, found := lookupValue(data, segments)
+	assert.True(t, found)
+	assert.Equal(t, data, value)
+
+	for _, valuePath := range []string{"", "$a", "a..b", "a[0"} {
+		_, err := parseValuePath(valuePath)
+		assert.Error(t, err, valuePath)
+	}
+}
+
+func TestProcedureSet_Register(t *testing.T) {
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep)
+	registry.Assign("CCC.C01.TR01", AssessmentStep(passingAssessmentStep).String())
+	require.NoError(t, loadProcedures(t).Register(registry))
+
+	steps, err := registry.StepsFor("CCC.C01.TR01")
+	require.NoError(t, err)
+	require.Len(t, steps, 2, "procedures are assigned after existing steps")
+	assert.Equal(t, "ccc.encryption-in-transit", steps[1].Name())
+
+	assessment, err := registry.NewAssessment("CCC.C08.TR01", "replication", testingApplicability)
+	require.NoError(t, err)
+	assert.Equal(t, Passed, assessment.Run(goodStorageTarget()))
+	assert.Equal(t, "ccc.replication", assessment.StepRecords[0].Name)
+	require.Len(t, assessment.Evidence, 1, "procedures return the values they checked")
+	assert.Equal(t, "Data is replicated to at least two regions, one of them us-east-1", assessment.Evidence[0].Description)
+	assert.Equal(t, `{"$.replication.copies":3,"$.replication.regions":["eu-west-1","us-east-1"]}`, assessment.Evidence[0].Content)
+	assert.Equal(t, "ccc.replication", assessment.Evidence[0].Step)
+}
+
+func TestProcedureSet_Validate(t *testing.T) {
+	catalog := &Catalog{}
+	require.NoError(t, catalog.LoadFile("file://test-data/good-ccc.yaml"))
+	assert.Empty(t, loadProcedures(t).Validate(WithCatalogs(catalog)))
+
+	procedures := &ProcedureSet{Procedures: []Procedure{
+		{Id: "p", RequirementId: "CCC.C01.TR01", Assertions: []Assertion{{Path: "a", Matches: "("}}},
+		{Id: "p", RequirementId: "MISSING"},
+	}}
+	var codes []DiagnosticCode
+	for _, diagnostic := range procedures.Validate(WithCatalogs(catalog)) {
+		codes = append(codes, diagnostic.Code)
+	}
+	assert.Equal(t, []DiagnosticCode{CodeInvalidAssertion, CodeDuplicateId, CodeUnknownRequirement, CodeMissingField}, codes)
+}
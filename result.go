@@ -1,6 +1,11 @@
 package gemara
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
 
 // Result is an enum representing the result of a control evaluation
 // This is designed to restrict the possible result values to a set of known states
@@ -24,6 +29,15 @@ var toString = map[Result]string{
 	Unknown:       "Unknown",
 }
 
+var stringToResult = map[string]Result{
+	"Not Run":        NotRun,
+	"Passed":         Passed,
+	"Failed":         Failed,
+	"Needs Review":   NeedsReview,
+	"Not Applicable": NotApplicable,
+	"Unknown":        Unknown,
+}
+
 func (r Result) String() string {
 	return toString[r]
 }
@@ -33,11 +47,37 @@ func (r Result) MarshalYAML() (interface{}, error) {
 	return r.String(), nil
 }
 
+// UnmarshalYAML ensures that Result can be deserialized from a YAML string
+func (r *Result) UnmarshalYAML(data []byte) error {
+	var s string
+	if err := loaders.UnmarshalYAML(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToResult[s]; ok {
+		*r = val
+		return nil
+	}
+	return fmt.Errorf("invalid Result: %s", s)
+}
+
 // MarshalJSON ensures that Result is serialized as a string in JSON
 func (r Result) MarshalJSON() ([]byte, error) {
 	return json.Marshal(r.String())
 }
 
+// UnmarshalJSON ensures that Result can be deserialized from a JSON string
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if val, ok := stringToResult[s]; ok {
+		*r = val
+		return nil
+	}
+	return fmt.Errorf("invalid Result: %s", s)
+}
+
 // UpdateAggregateResult compares the current result with the new result and returns the most severe of the two.
 func UpdateAggregateResult(previous Result, new Result) Result {
 	if new == NotRun {
@@ -47,6 +87,17 @@ func UpdateAggregateResult(previous Result, new Result) Result {
 		return previous
 	}
 
+	if new == NotApplicable {
+		// Not Applicable only stands when nothing else has a result
+		if previous == NotRun {
+			return NotApplicable
+		}
+		return previous
+	}
+	if previous == NotApplicable {
+		return new
+	}
+
 	if previous == Failed || new == Failed {
 		// Failed should not be overwritten by anything
 		// Failed should overwrite anything
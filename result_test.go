@@ -95,6 +95,24 @@ func TestUpdateAggregateResult(t *testing.T) {
 			new:      NeedsReview,
 			expected: NeedsReview,
 		},
+		{
+			name:     "NotApplicable should overwrite NotRun",
+			prev:     NotRun,
+			new:      NotApplicable,
+			expected: NotApplicable,
+		},
+		{
+			name:     "NotApplicable should not overwrite Passed",
+			prev:     Passed,
+			new:      NotApplicable,
+			expected: Passed,
+		},
+		{
+			name:     "Passed should overwrite NotApplicable",
+			prev:     NotApplicable,
+			new:      Passed,
+			expected: Passed,
+		},
 	}
 
 	for _, test := range tests {
@@ -106,3 +124,103 @@ func TestUpdateAggregateResult(t *testing.T) {
 		})
 	}
 }
+
+func TestResult_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Result
+		wantErr bool
+	}{
+		{
+			name: "Passed result",
+			data: "Passed",
+			want: Passed,
+		},
+		{
+			name: "Needs Review result",
+			data: "Needs Review",
+			want: NeedsReview,
+		},
+		{
+			name: "Not Applicable result",
+			data: "Not Applicable",
+			want: NotApplicable,
+		},
+		{
+			name:    "Invalid result",
+			data:    "Invalid",
+			want:    NotRun,
+			wantErr: true,
+		},
+		{
+			name:    "Case sensitive - lowercase",
+			data:    "passed",
+			want:    NotRun,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Result
+			err := r.UnmarshalYAML([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if r != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
+
+func TestResult_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Result
+		wantErr bool
+	}{
+		{
+			name: "Failed result",
+			data: `"Failed"`,
+			want: Failed,
+		},
+		{
+			name: "Not Run result",
+			data: `"Not Run"`,
+			want: NotRun,
+		},
+		{
+			name: "Unknown result",
+			data: `"Unknown"`,
+			want: Unknown,
+		},
+		{
+			name:    "Invalid result",
+			data:    `"Invalid"`,
+			want:    NotRun,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid JSON",
+			data:    `not json`,
+			want:    NotRun,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Result
+			err := r.UnmarshalJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if r != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, r)
+			}
+		})
+	}
+}
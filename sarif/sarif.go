@@ -121,7 +121,7 @@ func FromEvaluationLog(evaluationLog gemara.EvaluationLog, artifactURI string, c
 			if len(log.Steps) > 0 {
 				lastStep := log.Steps[len(log.Steps)-1]
 				if lastStep != nil {
-					logicalLocationName = lastStep.String()
+					logicalLocationName = lastStep.Name()
 				}
 			}
 
@@ -0,0 +1,153 @@
+package gemara
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// SaveOption defines an option to configure how a document is serialized.
+type SaveOption func(o *saveOptions)
+
+type saveOptions struct {
+	comments         []byte
+	existingComments bool
+}
+
+// WithCommentsFrom is a SaveOption that carries the comments of original, a YAML
+// rendering of the same document, over to the fields they were attached to.
+// Comments on fields that no longer exist are dropped. It has no effect on JSON output.
+func WithCommentsFrom(original []byte) SaveOption {
+	return func(o *saveOptions) {
+		o.comments = original
+	}
+}
+
+// WithExistingComments is a SaveOption that keeps the comments of the file being
+// overwritten by Save, so that programmatic edits to a hand-written document leave
+// its comments in place.
+func WithExistingComments() SaveOption {
+	return func(o *saveOptions) {
+		o.existingComments = true
+	}
+}
+
+// Marshal encodes v, typically a GuidanceDocument, Catalog, Policy or EvaluationLog, in the
+// canonical layout for format. Documents start with their metadata, followed by the other
+// fields in schema order, with empty optional fields omitted. YAML uses two space indentation,
+// indented sequences and literal blocks for multiline strings; JSON uses two space indentation.
+// Both end with a newline.
+func Marshal(v interface{}, format Format, opts ...SaveOption) ([]byte, error) {
+	options := &saveOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if doc, ok := v.(Document); ok {
+		ordered, err := orderDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		v = ordered
+	}
+	switch format {
+	case FormatYAML:
+		encodeOptions := []yaml.EncodeOption{
+			yaml.Indent(2),
+			yaml.IndentSequence(true),
+			yaml.UseLiteralStyleIfMultiline(true),
+		}
+		if len(options.comments) > 0 {
+			comments := yaml.CommentMap{}
+			var discard interface{}
+			if err := yaml.UnmarshalWithOptions(options.comments, &discard, yaml.CommentToMap(comments)); err != nil {
+				return nil, fmt.Errorf("error reading comments: %w", err)
+			}
+			encodeOptions = append(encodeOptions, yaml.WithComment(comments))
+		}
+		data, err := yaml.MarshalWithOptions(v, encodeOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding YAML: %w", err)
+		}
+		return data, nil
+	case FormatJSON:
+		var compact bytes.Buffer
+		if err := encodeOrderedJSON(&compact, v); err != nil {
+			return nil, fmt.Errorf("error encoding JSON: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, compact.Bytes(), "", "  "); err != nil {
+			return nil, fmt.Errorf("error encoding JSON: %w", err)
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported format: %s", format)
+}
+
+// Save writes v in the canonical layout for format to a file URI or plain path,
+// replacing the file if it exists.
+func Save(v interface{}, uri string, format Format, opts ...SaveOption) error {
+	if scheme := uriScheme(uri); scheme != "" && scheme != "file" {
+		return fmt.Errorf("unsupported scheme: %s", scheme)
+	}
+	filePath := strings.TrimPrefix(uri, "file://")
+
+	options := &saveOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.existingComments && format == FormatYAML {
+		existing, err := os.ReadFile(filePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("error reading %s: %w", filePath, err)
+		}
+		if len(existing) > 0 {
+			opts = append(opts, WithCommentsFrom(existing))
+		}
+	}
+
+	data, err := Marshal(v, format, opts...)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, data, 0600); err != nil {
+		return fmt.Errorf("error writing %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// Save writes the GuidanceDocument to a file URI or plain path in the given format.
+func (g *GuidanceDocument) Save(uri string, format Format, opts ...SaveOption) error {
+	return Save(g, uri, format, opts...)
+}
+
+// Save writes the Catalog to a file URI or plain path in the given format.
+func (c *Catalog) Save(uri string, format Format, opts ...SaveOption) error {
+	return Save(c, uri, format, opts...)
+}
+
+// Save writes the Policy to a file URI or plain path in the given format.
+func (p *Policy) Save(uri string, format Format, opts ...SaveOption) error {
+	return Save(p, uri, format, opts...)
+}
+
+// Save writes the EvaluationLog to a file URI or plain path in the given format.
+func (e *EvaluationLog) Save(uri string, format Format, opts ...SaveOption) error {
+	return Save(e, uri, format, opts...)
+}
+
+// WriteFile writes the document to a file URI or plain path in the format implied by its
+// extension, mirroring LoadFile. Comments already present in the file are kept.
+func WriteFile(doc Document, targetPath string) error {
+	format, err := FormatFromPath(targetPath)
+	if err != nil {
+		return err
+	}
+	return doc.Save(targetPath, format, WithExistingComments())
+}
@@ -0,0 +1,108 @@
+package gemara
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSave_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourcePath string
+	}{
+		{"Guidance", "file://test-data/good-aigf.yaml"},
+		{"Catalog", "file://test-data/good-osps.yml"},
+		{"Policy", "file://test-data/good-osps-policy.yaml"},
+		{"EvaluationLog", "file://test-data/good-evaluation-log.yaml"},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{FormatYAML, FormatJSON} {
+			t.Run(tt.name+" "+string(format), func(t *testing.T) {
+				doc, err := Load(tt.sourcePath)
+				require.NoError(t, err)
+
+				target := filepath.Join(t.TempDir(), "doc."+string(format))
+				require.NoError(t, doc.Save(target, format))
+
+				reloaded, err := Load("file://" + target)
+				require.NoError(t, err)
+				assert.Equal(t, doc.GetMetadata(), reloaded.GetMetadata())
+
+				// Saving a reloaded document reproduces the file byte for byte.
+				saved, err := os.ReadFile(target)
+				require.NoError(t, err)
+				resaved, err := Marshal(reloaded, format)
+				require.NoError(t, err)
+				assert.Equal(t, string(saved), string(resaved))
+			})
+		}
+	}
+}
+
+func TestMarshal_Canonical(t *testing.T) {
+	catalog := &Catalog{
+		Title: "Example",
+		Metadata: Metadata{
+			Id:          "EX",
+			Description: "first line\nsecond line",
+			Author:      Actor{Id: "ossf", Name: "OpenSSF", Type: Human},
+		},
+		Families: []Family{{Id: "F", Title: "Family", Description: "A family"}},
+	}
+	data, err := Marshal(catalog, FormatYAML)
+	require.NoError(t, err)
+	want := `metadata:
+  id: EX
+  description: |-
+    first line
+    second line
+  author:
+    id: ossf
+    name: OpenSSF
+    type: Human
+title: Example
+families:
+  - id: F
+    title: Family
+    description: A family
+`
+	assert.Equal(t, want, string(data))
+
+	_, err = Marshal(catalog, Format("toml"))
+	assert.ErrorContains(t, err, "unsupported format: toml")
+}
+
+func TestSave_Comments(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "policy.yaml")
+	original, err := os.ReadFile("test-data/good-osps-policy.yaml")
+	require.NoError(t, err)
+	original = append([]byte("# Maintained by the OSPO.\n"), original...)
+	require.NoError(t, os.WriteFile(target, original, 0600))
+
+	policy := &Policy{}
+	require.NoError(t, policy.LoadFile("file://"+target))
+	policy.Metadata.Version = "1.1.0"
+
+	require.NoError(t, policy.Save(target, FormatYAML))
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(string(data), "# Maintained by the OSPO."))
+
+	require.NoError(t, os.WriteFile(target, original, 0600))
+	require.NoError(t, WriteFile(policy, target))
+	data, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Maintained by the OSPO.\nmetadata:\n")
+	assert.Contains(t, string(data), "version: 1.1.0")
+}
+
+func TestSave_Errors(t *testing.T) {
+	catalog := &Catalog{}
+	assert.ErrorContains(t, catalog.Save("https://example.com/catalog.yaml", FormatYAML), "unsupported scheme: https")
+	assert.ErrorContains(t, WriteFile(catalog, filepath.Join(t.TempDir(), "catalog.txt")), "unsupported file extension: .txt")
+}
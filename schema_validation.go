@@ -0,0 +1,168 @@
+package gemara
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+	cueyaml "cuelang.org/go/encoding/yaml"
+)
+
+// CodeSchemaViolation indicates that a document does not conform to its schema definition.
+const CodeSchemaViolation DiagnosticCode = "schema-violation"
+
+// schemaFiles holds the CUE definitions the Go types are generated from, so that documents
+// are checked against the schema itself rather than a translation of it.
+//
+//go:embed schemas/*.cue
+var schemaFiles embed.FS
+
+var (
+	loadSchema sync.Once
+	schemaErr  error
+	// schemaMu guards cueContext and schemaValue, which are not safe for concurrent use.
+	schemaMu    sync.Mutex
+	cueContext  *cue.Context
+	schemaValue cue.Value
+)
+
+// ValidateAgainstSchema checks a YAML or JSON document against the CUE definition for kind,
+// as `cue vet -c -d '#<kind>'` would, enforcing required fields, closed structs, enumerations
+// and constraints such as #Email, #Datetime and the mapping strength range. Every violation is
+// returned as a Diagnostic whose Path locates the offending field. As with cue vet, fields the
+// definition does not allow are only reported once the document has no invalid values. An
+// error is returned when the kind is unknown or the document cannot be parsed.
+func ValidateAgainstSchema(kind Kind, data []byte) (Diagnostics, error) {
+	loadSchema.Do(func() {
+		cueContext = cuecontext.New()
+		schemaValue, schemaErr = buildSchema(cueContext)
+	})
+	if schemaErr != nil {
+		return nil, fmt.Errorf("error compiling embedded schema: %w", schemaErr)
+	}
+	if _, err := NewDocument(kind); err != nil {
+		return nil, err
+	}
+
+	// JSON is a subset of YAML, so both formats are decoded by the YAML parser.
+	file, err := cueyaml.Extract("document.yaml", data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	definition := schemaValue.LookupPath(cue.MakePath(cue.Def(string(kind))))
+	if err := definition.Err(); err != nil {
+		return nil, fmt.Errorf("error compiling embedded schema: %w", err)
+	}
+	document := cueContext.BuildFile(file)
+	if err := document.Err(); err != nil {
+		return nil, fmt.Errorf("error decoding document: %w", err)
+	}
+	err = document.Unify(definition).Validate(cue.Concrete(true), cue.All())
+	if err == nil {
+		return nil, nil
+	}
+	return schemaViolations(cueerrors.Errors(err)), nil
+}
+
+// buildSchema compiles the embedded CUE package.
+func buildSchema(ctx *cue.Context) (cue.Value, error) {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return cue.Value{}, err
+	}
+	instance := build.NewContext().NewInstance("schemas", nil)
+	for _, entry := range entries {
+		name := path.Join("schemas", entry.Name())
+		source, err := schemaFiles.ReadFile(name)
+		if err != nil {
+			return cue.Value{}, err
+		}
+		file, err := parser.ParseFile(name, source)
+		if err != nil {
+			return cue.Value{}, err
+		}
+		if err := instance.AddSyntax(file); err != nil {
+			return cue.Value{}, err
+		}
+	}
+	value := ctx.BuildInstance(instance)
+	return value, value.Err()
+}
+
+// schemaViolations converts CUE errors into one diagnostic per field. CUE reports a value that
+// matches none of the members of a disjunction, such as an enumeration, as a summary followed
+// by one conflict per member; those are merged into a single message listing the members.
+func schemaViolations(errs []cueerrors.Error) Diagnostics {
+	var diagnostics Diagnostics
+	for i := 0; i < len(errs); i++ {
+		fieldPath := cuePath(errs[i].Path())
+		format, args := errs[i].Msg()
+		var text string
+		switch {
+		case strings.HasSuffix(format, "errors in empty disjunction:"):
+			var members []string
+			value := ""
+			for i+1 < len(errs) && cuePath(errs[i+1].Path()) == fieldPath {
+				i++
+				memberFormat, memberArgs := errs[i].Msg()
+				if memberFormat != "conflicting values %s and %s" || len(memberArgs) != 2 {
+					continue
+				}
+				members = append(members, fmt.Sprint(memberArgs[0]))
+				value = fmt.Sprint(memberArgs[1])
+			}
+			if len(members) == 0 {
+				text = "value does not match any of the allowed values"
+			} else {
+				text = fmt.Sprintf("invalid value %s (must be one of %s)", value, strings.Join(members, ", "))
+			}
+		case format == "incomplete value %v" && len(args) == 1:
+			text = fmt.Sprintf("missing required field (%v)", args[0])
+		default:
+			text = fmt.Sprintf(format, args...)
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Code:     CodeSchemaViolation,
+			Severity: SeverityError,
+			Path:     fieldPath,
+			Message:  text,
+		})
+	}
+	return diagnostics
+}
+
+// cuePath renders the selectors of a CUE error as a field path, e.g. "controls[2].family".
+func cuePath(selectors []string) string {
+	var fieldPath strings.Builder
+	for _, selector := range selectors {
+		if strings.HasPrefix(selector, "#") {
+			continue
+		}
+		if _, err := strconv.Atoi(selector); err == nil {
+			fmt.Fprintf(&fieldPath, "[%s]", selector)
+			continue
+		}
+		if unquoted, err := strconv.Unquote(selector); err == nil {
+			selector = unquoted
+		}
+		if fieldPath.Len() > 0 {
+			fieldPath.WriteByte('.')
+		}
+		fieldPath.WriteString(selector)
+	}
+	if fieldPath.Len() == 0 {
+		return "."
+	}
+	return fieldPath.String()
+}
@@ -0,0 +1,164 @@
+package gemara
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAgainstSchema_Files(t *testing.T) {
+	tests := []struct {
+		file string
+		kind Kind
+	}{
+		{"good-ccc.yaml", KindCatalog},
+		{"good-imported-catalog.yaml", KindCatalog},
+		{"good-aigf.yaml", KindGuidanceDocument},
+		{"good-extended-guidance.yaml", KindGuidanceDocument},
+		{"good-osps-policy.yaml", KindPolicy},
+		{"good-evaluation-log.yaml", KindEvaluationLog},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("test-data", tt.file))
+			require.NoError(t, err)
+			diagnostics, err := ValidateAgainstSchema(tt.kind, data)
+			require.NoError(t, err)
+			assert.Empty(t, diagnostics)
+		})
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	const evaluation = `
+evaluations:
+  - name: CTL-1
+    result: Passed
+    message: ok
+    control:
+      reference-id: CAT
+      entry-id: CTL-1
+    assessment-logs:
+      - requirement:
+          reference-id: CAT
+          entry-id: CTL-1.1
+        description: check
+        result: %s
+        message: ok
+        applicability: []
+        steps: []
+        start: %s
+`
+	tests := []struct {
+		name string
+		kind Kind
+		data string
+		want map[string]string
+	}{
+		{
+			name: "valid evaluation log",
+			kind: KindEvaluationLog,
+			data: fmt.Sprintf(evaluation, "Passed", "2025-08-22T16:02:00Z"),
+			want: map[string]string{},
+		},
+		{
+			name: "bad enum and datetime",
+			kind: KindEvaluationLog,
+			data: fmt.Sprintf(evaluation, "Great", "yesterday"),
+			want: map[string]string{
+				"evaluations[0].assessment-logs[0].result": `invalid value "Great" (must be one of "Failed", "Needs Review"`,
+				"evaluations[0].assessment-logs[0].start":  `invalid value "yesterday" (does not satisfy time.Format`,
+			},
+		},
+		{
+			name: "empty evaluations",
+			kind: KindEvaluationLog,
+			data: `evaluations: []`,
+			want: map[string]string{"evaluations": "incompatible list lengths"},
+		},
+		{
+			name: "catalog with bad email and strength",
+			kind: KindCatalog,
+			data: `{
+  "title": "Example",
+  "metadata": {
+    "id": "EX",
+    "description": "example",
+    "author": {"id": "me", "name": "Me", "type": "Human", "contact": {"name": "Me", "email": "not-an-email"}}
+  },
+  "controls": [{
+    "id": "CTL-1", "title": "Control", "objective": "objective", "family": "FAM", "assessment-requirements": [],
+    "guideline-mappings": [{"reference-id": "EXT", "entries": [{"reference-id": "G-1", "strength": 11}]}]
+  }]
+}`,
+			want: map[string]string{
+				"metadata.author.contact.email":                         `invalid value "not-an-email" (out of bound =~`,
+				"controls[0].guideline-mappings[0].entries[0].strength": "invalid value 11 (out of bound <=10)",
+			},
+		},
+		{
+			name: "catalog with unknown field",
+			kind: KindCatalog,
+			data: `
+title: Example
+controls:
+  - id: CTL-1
+    title: Control
+    objective: objective
+    family: FAM
+    assessment-requirements: []
+    owner: nobody
+`,
+			want: map[string]string{"controls[0].owner": "field not allowed"},
+		},
+		{
+			name: "assessment reference differs from control",
+			kind: KindEvaluationLog,
+			data: strings.Replace(fmt.Sprintf(evaluation, "Passed", "2025-08-22T16:02:00Z"), "reference-id: CAT\n          entry-id: CTL-1.1", "reference-id: OTHER\n          entry-id: CTL-1.1", 1),
+			want: map[string]string{
+				"evaluations[0].assessment-logs[0].requirement.reference-id": `conflicting values "CAT" and "OTHER"`,
+			},
+		},
+		{
+			name: "policy missing required fields",
+			kind: KindPolicy,
+			data: `title: Example`,
+			want: map[string]string{
+				"metadata.id":          "missing required field (string)",
+				"metadata.description": "missing required field (string)",
+				"metadata.author.id":   "missing required field (string)",
+				"metadata.author.name": "missing required field (string)",
+				"metadata.author.type": `missing required field ("Human" | "Software" | "Software-Assisted")`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics, err := ValidateAgainstSchema(tt.kind, []byte(tt.data))
+			require.NoError(t, err)
+			got := make(map[string]string)
+			for _, d := range diagnostics {
+				assert.Equal(t, CodeSchemaViolation, d.Code)
+				assert.Equal(t, SeverityError, d.Severity)
+				got[d.Path] = d.Message
+			}
+			require.Len(t, got, len(tt.want), "%v", diagnostics)
+			for path, message := range tt.want {
+				assert.Contains(t, got[path], message, path)
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchema_Errors(t *testing.T) {
+	_, err := ValidateAgainstSchema(Kind("Checklist"), []byte(`title: x`))
+	assert.ErrorContains(t, err, "unsupported document kind: Checklist")
+
+	_, err = ValidateAgainstSchema(KindCatalog, []byte("title: [unclosed"))
+	assert.ErrorContains(t, err, "error decoding document")
+}
@@ -118,6 +118,8 @@ package schemas
 	label:       string
 	description: string
 	"accepted-values"?: [...string] @go(AcceptedValues)
+	// Value is the value chosen by the organization, which must be one of the accepted values when they are listed.
+	value?: string
 }
 
 // GuidanceImport defines how to import guidance documents with optional exclusions and constraints.
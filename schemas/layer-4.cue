@@ -40,7 +40,7 @@ package schemas
 	// Applicability is elevated from the Layer 2 Assessment Requirement to aid in execution and reporting.
 	applicability: [...string] @go(Applicability,type=[]string)
 	// Steps are sequential actions taken as part of the assessment, which may halt the assessment if a failure occurs.
-	steps: [...#AssessmentStep]
+	steps: [...#AssessmentStep] @go(Steps,type=StepList)
 	// Steps-executed is the number of steps that were executed as part of the assessment.
 	"steps-executed"?: int @go(StepsExecuted)
 	// Start is the timestamp when the assessment began.
@@ -51,10 +51,54 @@ package schemas
 	recommendation?: string
 	// ConfidenceLevel indicates the evaluator's confidence level in this specific assessment result.
 	"confidence-level"?: #ConfidenceLevel @go(ConfidenceLevel)
+	// Step-records trace the outcome of each step that was executed, in the order the steps ran.
+	"step-records"?: [...#StepRecord] @go(StepRecords)
+	// Evidence justifies the result of the assessment.
+	evidence?: [...#Evidence] @go(Evidence)
+	// Parameters are the values of the assessment plan parameters the steps were run with, keyed by parameter id.
+	parameters?: {[string]: string} @go(Parameters)
 }
 
 #AssessmentStep: string @go(-)
 
+// StepRecord captures the outcome of a single assessment step execution.
+#StepRecord: {
+	// Name identifies the step, matching its entry in the steps of the assessment.
+	name: string
+	// Result is the outcome reported by the step.
+	result: #Result
+	// Message is the context reported by the step.
+	message: string
+	// ConfidenceLevel is the confidence reported by the step.
+	"confidence-level"?: #ConfidenceLevel @go(ConfidenceLevel)
+	// Start is the timestamp when the step began.
+	start: #Datetime
+	// End is the timestamp when the step concluded.
+	end?: #Datetime
+	// Duration is the time the step took to run, in Go duration format such as "1.5s".
+	duration?: string
+	// Stack is the stack trace captured when the step panicked.
+	stack?: string
+}
+
+// Evidence is an item collected to justify an assessment result.
+#Evidence: {
+	// Description explains what the evidence shows.
+	description: string
+	// Content holds the evidence inline, such as a configuration snippet or command output.
+	content?: string
+	// Uri references evidence kept elsewhere, such as a file or an object in an evidence store.
+	uri?: string
+	// Digest identifies the content of the evidence as algorithm:hex, such as "sha256:9f86d0...".
+	digest?: =~"^[a-z0-9]+:[a-f0-9]+$"
+	// Collected is the timestamp when the evidence was collected.
+	collected: #Datetime
+	// Collector identifies the actor that collected the evidence.
+	collector?: #Actor @go(Collector,optional=nillable)
+	// Step names the assessment step that collected the evidence.
+	step?: string
+}
+
 #Result: "Not Run" | "Passed" | "Failed" | "Needs Review" | "Not Applicable" | "Unknown" @go(-)
 
 // ConfidenceLevel indicates the evaluator's confidence level in an assessment result.
@@ -0,0 +1,200 @@
+package gemara
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/parser"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
+
+// Format is the encoding of a serialized document.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// FormatFromPath returns the format implied by the file extension of a path or URI.
+func FormatFromPath(sourcePath string) (Format, error) {
+	ext := path.Ext(sourcePath)
+	switch ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unsupported file extension: %s", ext)
+}
+
+// Source is a serialized document that can be decoded with Decode or LoadSource.
+type Source interface {
+	// Open returns the content of the document. The caller must close it.
+	Open() (io.ReadCloser, error)
+	// Format returns the encoding of the content.
+	Format() Format
+	// String describes the source for error messages.
+	String() string
+}
+
+type readerSource struct {
+	reader io.Reader
+	format Format
+}
+
+// FromReader returns a Source that reads a document in the given format from r.
+// The reader is consumed by the first call to Open.
+func FromReader(r io.Reader, format Format) Source {
+	return &readerSource{reader: r, format: format}
+}
+
+func (s *readerSource) Open() (io.ReadCloser, error) { return io.NopCloser(s.reader), nil }
+func (s *readerSource) Format() Format               { return s.format }
+func (s *readerSource) String() string               { return fmt.Sprintf("%s reader", s.format) }
+
+type fetchedSource struct {
+	uri     string
+	fetcher Fetcher
+}
+
+// FromURI returns a Source that retrieves uri with fetcher, or with DefaultFetcher when fetcher is nil.
+// The format is derived from the file extension of uri.
+func FromURI(uri string, fetcher Fetcher) Source {
+	if fetcher == nil {
+		fetcher = DefaultFetcher
+	}
+	return &fetchedSource{uri: uri, fetcher: fetcher}
+}
+
+// FromPath returns a Source for a plain file path, which may be relative to the working directory.
+func FromPath(filePath string) Source {
+	return FromURI(filePath, FileFetcher{})
+}
+
+// FromFS returns a Source for a file in fsys, such as an embed.FS.
+func FromFS(fsys fs.FS, name string) Source {
+	return FromURI(name, FSFetcher{FS: fsys})
+}
+
+func (s *fetchedSource) Open() (io.ReadCloser, error) { return s.fetcher.Fetch(s.uri) }
+func (s *fetchedSource) String() string               { return s.uri }
+
+func (s *fetchedSource) Format() Format {
+	format, err := FormatFromPath(s.uri)
+	if err != nil {
+		return Format(path.Ext(s.uri))
+	}
+	return format
+}
+
+type bytesSource struct {
+	name   string
+	data   []byte
+	format Format
+}
+
+// FromBytes returns a Source for data that was read from name, such as a file path or URI.
+// The name identifies the document in errors and positions, and its file extension gives the format.
+func FromBytes(name string, data []byte) Source {
+	format, err := FormatFromPath(name)
+	if err != nil {
+		format = Format(path.Ext(name))
+	}
+	return &bytesSource{name: name, data: data, format: format}
+}
+
+func (s *bytesSource) Open() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.data)), nil
+}
+func (s *bytesSource) Format() Format { return s.format }
+func (s *bytesSource) String() string { return s.name }
+
+// Decode reads the document from src into target, which is typically a pointer to a
+// GuidanceDocument, Catalog, Policy or EvaluationLog. A document that cannot be decoded is
+// reported with a *DecodeError locating the problem, when its position is known.
+func Decode(src Source, target interface{}) error {
+	_, err := DecodeWithPositions(src, target)
+	return err
+}
+
+// DecodeWithPositions decodes the document from src into target as Decode does, and returns
+// the position of every field and list item of the document, recorded from the same parse.
+// The positions are returned whenever the document could be parsed, even if it could not be
+// decoded into target, so that schema diagnostics can still be located.
+func DecodeWithPositions(src Source, target interface{}) (Positions, error) {
+	reader, err := src.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", src, err)
+	}
+
+	switch src.Format() {
+	case FormatYAML:
+		file, err := parser.ParseBytes(data, 0)
+		if err != nil {
+			return nil, decodeError(src.String(), nil, fmt.Errorf("error decoding YAML: %w", err))
+		}
+		positions := locate(src.String(), file)
+		if len(file.Docs) == 0 || file.Docs[0].Body == nil {
+			return positions, fmt.Errorf("%s: error decoding YAML: %w", src, io.EOF)
+		}
+		if err := yaml.NodeToValue(file.Docs[0].Body, target); err != nil {
+			return positions, decodeError(src.String(), positions, fmt.Errorf("error decoding YAML: %w", err))
+		}
+		return positions, nil
+	case FormatJSON:
+		// JSON is decoded with encoding/json, which has no positions; they come from parsing the
+		// same bytes as YAML, of which JSON is a subset.
+		var positions Positions
+		if file, err := parser.ParseBytes(data, 0); err == nil {
+			positions = locate(src.String(), file)
+		}
+		if err := loaders.DecodeJSON(bytes.NewReader(data), target); err != nil {
+			return positions, decodeError(src.String(), positions, fmt.Errorf("error loading json: %w", err))
+		}
+		return positions, nil
+	}
+	return nil, fmt.Errorf("unsupported format for %s: %s", src, src.Format())
+}
+
+// LoadSource loads a document of any kind from src, detecting the kind as Load does.
+func LoadSource(src Source) (Document, error) {
+	reader, err := src.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	_ = reader.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", src, err)
+	}
+	// Decode the retrieved bytes twice, under the name of src so that errors locate the document.
+	named := &bytesSource{name: src.String(), data: data, format: src.Format()}
+
+	var fields map[string]interface{}
+	if err := Decode(named, &fields); err != nil {
+		return nil, err
+	}
+	kind, err := detectKind(fields)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	doc, err := NewDocument(kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := Decode(named, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
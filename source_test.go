@@ -0,0 +1,80 @@
+package gemara
+
+import (
+	"bytes"
+	"embed"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed test-data/good-osps.yml test-data/good-evaluation-log.yaml
+var embeddedTestData embed.FS
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Format
+		wantErr string
+	}{
+		{"catalog.yaml", FormatYAML, ""},
+		{"file://catalog.yml", FormatYAML, ""},
+		{"https://example.com/catalog.json", FormatJSON, ""},
+		{"catalog.txt", "", "unsupported file extension: .txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatFromPath(tt.path)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	data, err := os.ReadFile("test-data/good-osps.yml")
+	require.NoError(t, err)
+
+	sources := map[string]Source{
+		"reader":        FromReader(bytes.NewReader(data), FormatYAML),
+		"relative path": FromPath("test-data/good-osps.yml"),
+		"file uri":      FromURI("file://test-data/good-osps.yml", nil),
+		"embed.FS":      FromFS(embeddedTestData, "test-data/good-osps.yml"),
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			catalog := &Catalog{}
+			require.NoError(t, Decode(src, catalog))
+			assert.Equal(t, "OSPS-B", catalog.Metadata.Id)
+			assert.NotEmpty(t, catalog.Controls)
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	err := Decode(FromReader(strings.NewReader(`{"title": "x", "unknown": true}`), FormatJSON), &Catalog{})
+	assert.ErrorContains(t, err, "error loading json")
+
+	err = Decode(FromPath("test-data/unsupported.txt"), &Catalog{})
+	assert.ErrorContains(t, err, "unsupported format for test-data/unsupported.txt")
+
+	err = Decode(FromPath("test-data/missing.yaml"), &Catalog{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadSource(t *testing.T) {
+	doc, err := LoadSource(FromFS(embeddedTestData, "test-data/good-evaluation-log.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, KindEvaluationLog, doc.Kind())
+	assert.Len(t, doc.(*EvaluationLog).Evaluations, 3)
+
+	_, err = LoadSource(FromReader(strings.NewReader("title: x"), FormatYAML))
+	assert.ErrorContains(t, err, "yaml reader: unable to detect document kind")
+}
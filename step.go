@@ -0,0 +1,162 @@
+package gemara
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ossf/gemara/internal/loaders"
+)
+
+// Step is a single action of an assessment, queued in AssessmentLog.Steps.
+//
+// Name identifies the step when the log is serialized and in a StepRegistry, so it should be
+// stable across builds. Run assesses the target data in input. The context is cancelled when
+// the assessment is cancelled or a step or assessment timeout expires, and long-running steps,
+// such as network probes, should return promptly once it is done.
+//
+// AssessmentStep and StepFunc adapt ordinary functions to Step.
+type Step interface {
+	Name() string
+	Run(ctx context.Context, input StepInput) StepResult
+}
+
+// StepInput is what a Step receives when it is run.
+type StepInput struct {
+	// Payload is the target data the assessment is run against.
+	Payload interface{}
+
+	// Parameters are the values bound to the parameters of the assessment's plan, keyed by
+	// parameter id, see AssessmentLog.BindParameters. They must not be modified.
+	Parameters map[string]string
+}
+
+// StepResult is the outcome reported by a Step.
+type StepResult struct {
+	Result Result
+
+	// Message provides context about the result. It may be an error string or other descriptive text.
+	Message string
+
+	ConfidenceLevel ConfidenceLevel
+
+	// Evidence justifies the result. It is kept in AssessmentLog.Evidence, with the collection
+	// time, the name of the step and the collector set by WithEvidenceCollector filled in when
+	// they are missing.
+	Evidence []Evidence
+}
+
+// StepFunc adapts a function to the Step interface. Like AssessmentStep, it is named after the
+// fully qualified name of the function; use NamedStep to give a closure a stable name.
+type StepFunc func(ctx context.Context, input StepInput) StepResult
+
+// Name returns the fully qualified name of the function.
+func (f StepFunc) Name() string {
+	return functionName(f)
+}
+
+// Run calls f.
+func (f StepFunc) Run(ctx context.Context, input StepInput) StepResult {
+	return f(ctx, input)
+}
+
+// NamedStep returns a Step that runs step and reports name from Name. It is useful for
+// closures, whose generated function names such as "example.com/plugin.init.func1" are
+// not stable across builds.
+func NamedStep(name string, step Step) Step {
+	if named, ok := step.(namedStep); ok {
+		step = named.step
+	}
+	return namedStep{name: name, step: step}
+}
+
+type namedStep struct {
+	name string
+	step Step
+}
+
+func (s namedStep) Name() string { return s.name }
+
+func (s namedStep) Run(ctx context.Context, input StepInput) StepResult {
+	return s.step.Run(ctx, input)
+}
+
+// unresolvedStep stands in for a step that was loaded by name from a serialized log.
+// Running it reports Unknown, since the original implementation is not available.
+type unresolvedStep struct {
+	name string
+}
+
+func (s unresolvedStep) Name() string { return s.name }
+
+func (s unresolvedStep) Run(context.Context, StepInput) StepResult {
+	return StepResult{
+		Result:          Unknown,
+		Message:         fmt.Sprintf("step %s was loaded by name and has no registered implementation", s.name),
+		ConfidenceLevel: Undetermined,
+	}
+}
+
+// stepName returns the name of step, tolerating steps that were never set.
+func stepName(step Step) string {
+	if step == nil {
+		return "<unknown function>"
+	}
+	return step.Name()
+}
+
+// StepList is the sequence of steps of an assessment. It is serialized as the names of the steps.
+// Steps loaded from YAML or JSON keep their names, and report Unknown when run until they are
+// replaced with their implementations, see WithStepRegistry and StepRegistry.Rehydrate.
+//
+// StepList replaces the []AssessmentStep type of AssessmentLog.Steps. An AssessmentStep is a
+// Step, so slice literals migrate by changing their type: StepList{stepA, stepB}.
+type StepList []Step
+
+// Names returns the name of every step, in order.
+func (l StepList) Names() []string {
+	names := make([]string, len(l))
+	for i, step := range l {
+		names[i] = stepName(step)
+	}
+	return names
+}
+
+func (l StepList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Names())
+}
+
+func (l StepList) MarshalYAML() (interface{}, error) {
+	return l.Names(), nil
+}
+
+// UnmarshalYAML restores the steps from the names written by MarshalYAML.
+func (l *StepList) UnmarshalYAML(data []byte) error {
+	var names []string
+	if err := loaders.UnmarshalYAML(data, &names); err != nil {
+		return err
+	}
+	*l = stepsNamed(names)
+	return nil
+}
+
+// UnmarshalJSON restores the steps from the names written by MarshalJSON.
+func (l *StepList) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	*l = stepsNamed(names)
+	return nil
+}
+
+func stepsNamed(names []string) StepList {
+	if names == nil {
+		return nil
+	}
+	steps := make(StepList, len(names))
+	for i, name := range names {
+		steps[i] = unresolvedStep{name: name}
+	}
+	return steps
+}
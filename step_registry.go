@@ -0,0 +1,178 @@
+package gemara
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StepRegistry maps step names, as reported by Step.Name, to their implementations, so that
+// assessments loaded from a serialized EvaluationLog or a StepPlan can be run again.
+// It also records which steps assess each requirement. A StepRegistry is safe for concurrent use.
+type StepRegistry struct {
+	mu           sync.RWMutex
+	steps        map[string]Step
+	requirements map[string][]string
+}
+
+// NewStepRegistry returns an empty StepRegistry.
+func NewStepRegistry() *StepRegistry {
+	return &StepRegistry{
+		steps:        make(map[string]Step),
+		requirements: make(map[string][]string),
+	}
+}
+
+// Register adds steps under their names, replacing any step already registered with the same name.
+func (r *StepRegistry) Register(steps ...Step) {
+	for _, step := range steps {
+		r.RegisterAs(step.Name(), step)
+	}
+}
+
+// RegisterAs adds step under name, as NamedStep does. It is useful for closures, whose generated
+// function names such as "example.com/plugin.init.func1" are not stable across builds.
+// Logs that run the registered step serialize the name it was registered under.
+func (r *StepRegistry) RegisterAs(name string, step Step) {
+	if step.Name() != name {
+		step = NamedStep(name, step)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps[name] = step
+}
+
+// Lookup returns the step registered under name.
+func (r *StepRegistry) Lookup(name string) (Step, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	step, ok := r.steps[name]
+	return step, ok
+}
+
+// Steps returns the steps registered under names, in order. The error lists every name that
+// is not registered.
+func (r *StepRegistry) Steps(names []string) (StepList, error) {
+	steps := make(StepList, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		step, ok := r.Lookup(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		steps = append(steps, step)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unregistered steps: %s", strings.Join(missing, ", "))
+	}
+	return steps, nil
+}
+
+// Assign records the names of the steps that assess requirementId, replacing any previous assignment.
+// The steps do not need to be registered yet.
+func (r *StepRegistry) Assign(requirementId string, names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requirements[requirementId] = append([]string(nil), names...)
+}
+
+// appendAssignment adds names to the steps assigned to requirementId.
+func (r *StepRegistry) appendAssignment(requirementId string, names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requirements[requirementId] = append(r.requirements[requirementId], names...)
+}
+
+// StepsFor returns the steps assigned to requirementId. It returns no steps and no error when
+// nothing is assigned, and an error when an assigned step is not registered.
+func (r *StepRegistry) StepsFor(requirementId string) (StepList, error) {
+	r.mu.RLock()
+	names := r.requirements[requirementId]
+	r.mu.RUnlock()
+	if len(names) == 0 {
+		return nil, nil
+	}
+	steps, err := r.Steps(names)
+	if err != nil {
+		return nil, fmt.Errorf("requirement %s: %w", requirementId, err)
+	}
+	return steps, nil
+}
+
+// Requirements returns the sorted ids of the requirements that have steps assigned.
+func (r *StepRegistry) Requirements() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.requirements))
+	for id := range r.requirements {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// NewAssessment creates an AssessmentLog for requirementId that runs the steps assigned to it.
+func (r *StepRegistry) NewAssessment(requirementId string, description string, applicability []string) (*AssessmentLog, error) {
+	steps, err := r.StepsFor(requirementId)
+	if err != nil {
+		return nil, err
+	}
+	return newAssessment(requirementId, description, applicability, steps)
+}
+
+// Rehydrate replaces every step in log with the step registered under the same name, so that
+// a log loaded from YAML or JSON can be run again. Steps that are not registered are left in
+// place and reported in the error; running them reports Unknown.
+func (r *StepRegistry) Rehydrate(log *EvaluationLog) error {
+	missing := make(map[string]bool)
+	for _, evaluation := range log.Evaluations {
+		if evaluation == nil {
+			continue
+		}
+		for _, assessment := range evaluation.AssessmentLogs {
+			if assessment == nil {
+				continue
+			}
+			for i, step := range assessment.Steps {
+				name := stepName(step)
+				registered, ok := r.Lookup(name)
+				if !ok {
+					missing[name] = true
+					continue
+				}
+				assessment.Steps[i] = registered
+			}
+		}
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unregistered steps: %s", strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// StepPlan lists the names of the steps that assess each requirement, keyed by requirement id.
+// In YAML it is written as:
+//
+//	OSPS-AC-01.01:
+//	  - github.com/revanite-io/pvtr-github-repo/evaluation_plans/osps/access_control.orgRequiresMFA
+type StepPlan map[string][]string
+
+// LoadFile loads the plan from a YAML or JSON file at the provided path.
+// sourcePath is expected to be a file or https URI in the form file:///path/to/file.yaml or https://example.com/file.yaml.
+func (p *StepPlan) LoadFile(sourcePath string) error {
+	return loadFile(sourcePath, p)
+}
+
+// Apply assigns the steps of every requirement in plan, as Assign does.
+func (r *StepRegistry) Apply(plan StepPlan) {
+	for requirementId, names := range plan {
+		r.Assign(requirementId, names...)
+	}
+}
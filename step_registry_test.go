@@ -0,0 +1,130 @@
+package gemara
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	orgRequiresMFA       = "github.com/revanite-io/pvtr-github-repo/evaluation_plans/osps/access_control.orgRequiresMFA"
+	isCodeRepo           = "github.com/revanite-io/pvtr-github-repo/evaluation_plans/reusable_steps.IsCodeRepo"
+	branchRestrictPushes = "github.com/revanite-io/pvtr-github-repo/evaluation_plans/osps/access_control.branchProtectionRestrictsPushes"
+)
+
+func TestStepRegistry_Register(t *testing.T) {
+	registry := NewStepRegistry()
+	registry.Register(passingAssessmentStep, failingAssessmentStep)
+
+	step, ok := registry.Lookup(passingAssessmentStep.Name())
+	require.True(t, ok)
+	assert.Equal(t, Passed, step.Run(context.Background(), StepInput{}).Result)
+
+	registry.RegisterAs(orgRequiresMFA, AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
+		return Passed, "MFA required", High
+	}))
+	step, ok = registry.Lookup(orgRequiresMFA)
+	require.True(t, ok)
+	assert.Equal(t, orgRequiresMFA, step.Name())
+
+	_, err := registry.Steps([]string{orgRequiresMFA, "missing.One", "missing.Two"})
+	assert.EqualError(t, err, "unregistered steps: missing.One, missing.Two")
+}
+
+func TestStepRegistry_RegisterAs_Context(t *testing.T) {
+	type key struct{}
+	registry := NewStepRegistry()
+	registry.RegisterAs("example.contextStep", StepFunc(func(ctx context.Context, _ StepInput) StepResult {
+		if ctx.Value(key{}) != "value" {
+			return StepResult{Result: Failed, Message: "context was not passed", ConfidenceLevel: Low}
+		}
+		return StepResult{Result: Passed, Message: "context was passed", ConfidenceLevel: High}
+	}))
+	registry.Assign("REQ-01", "example.contextStep")
+
+	assessment, err := registry.NewAssessment("REQ-01", "description", testingApplicability)
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	assert.Equal(t, Passed, assessment.RunContext(ctx, nil))
+	assert.Equal(t, "example.contextStep", assessment.StepRecords[0].Name)
+}
+
+func TestStepRegistry_Rehydrate(t *testing.T) {
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+	assessment := log.Evaluations[0].AssessmentLogs[0]
+	assessment.Run(nil)
+	assert.Equal(t, Unknown, assessment.Result, "unregistered steps cannot run")
+
+	registry := NewStepRegistry()
+	registry.RegisterAs(orgRequiresMFA, AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
+		return Passed, "MFA required", High
+	}))
+	err := registry.Rehydrate(log)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), isCodeRepo)
+	assert.NotContains(t, err.Error(), orgRequiresMFA)
+
+	assert.Equal(t, Passed, assessment.Run(nil))
+	assert.Equal(t, "MFA required", assessment.Message)
+
+	data, err := Marshal(log, FormatYAML)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "- "+orgRequiresMFA+"\n")
+}
+
+func TestLoadEvaluationLog_WithStepRegistry(t *testing.T) {
+	log, err := LoadEvaluationLog("file://test-data/good-evaluation-log.yaml")
+	require.NoError(t, err)
+	assessment := log.Evaluations[0].AssessmentLogs[0]
+	assert.Equal(t, orgRequiresMFA, assessment.Steps[0].Name())
+	assert.Equal(t, Unknown, assessment.Run(nil), "steps are not resolved without a registry")
+
+	registry := NewStepRegistry()
+	registry.RegisterAs(orgRequiresMFA, passingAssessmentStep)
+	_, err = LoadEvaluationLog("file://test-data/good-evaluation-log.yaml", WithStepRegistry(registry))
+	assert.ErrorContains(t, err, isCodeRepo)
+	assert.NotContains(t, err.Error(), orgRequiresMFA)
+
+	for _, evaluation := range log.Evaluations {
+		for _, assessment := range evaluation.AssessmentLogs {
+			for _, name := range assessment.Steps.Names() {
+				if name != orgRequiresMFA {
+					registry.RegisterAs(name, needsReviewAssessmentStep)
+				}
+			}
+		}
+	}
+	log, err = LoadEvaluationLog("file://test-data/good-evaluation-log.yaml", WithStepRegistry(registry))
+	require.NoError(t, err)
+	assessment = log.Evaluations[0].AssessmentLogs[0]
+	assert.Equal(t, Passed, assessment.Run(nil))
+	assert.Equal(t, orgRequiresMFA, assessment.Steps[0].Name())
+}
+
+func TestStepPlan(t *testing.T) {
+	plan := StepPlan{}
+	require.NoError(t, plan.LoadFile("file://test-data/good-step-plan.yaml"))
+	assert.Equal(t, []string{isCodeRepo, branchRestrictPushes}, plan["OSPS-AC-03.01"])
+
+	registry := NewStepRegistry()
+	registry.Apply(plan)
+	assert.Equal(t, []string{"OSPS-AC-01.01", "OSPS-AC-03.01"}, registry.Requirements())
+
+	_, err := registry.NewAssessment("OSPS-AC-03.01", "description", testingApplicability)
+	assert.EqualError(t, err, "requirement OSPS-AC-03.01: unregistered steps: "+isCodeRepo+", "+branchRestrictPushes)
+
+	registry.RegisterAs(isCodeRepo, passingAssessmentStep)
+	registry.RegisterAs(branchRestrictPushes, needsReviewAssessmentStep)
+	assessment, err := registry.NewAssessment("OSPS-AC-03.01", "description", testingApplicability)
+	require.NoError(t, err)
+	assert.Equal(t, NeedsReview, assessment.Run(nil))
+
+	steps, err := registry.StepsFor("OSPS-UNKNOWN")
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+
+	assert.ErrorContains(t, plan.LoadFile("file://test-data/unsupported.txt"), "unsupported file extension: .txt")
+}
@@ -7,18 +7,18 @@ var (
 	testingApplicability = []string{"test-applicability"}
 
 	// Assessment Results
-	passingAssessmentStep = func(interface{}) (Result, string, ConfidenceLevel) {
+	passingAssessmentStep = AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
 		return Passed, "", High
-	}
-	failingAssessmentStep = func(interface{}) (Result, string, ConfidenceLevel) {
+	})
+	failingAssessmentStep = AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
 		return Failed, "", Low
-	}
-	needsReviewAssessmentStep = func(interface{}) (Result, string, ConfidenceLevel) {
+	})
+	needsReviewAssessmentStep = AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
 		return NeedsReview, "", Medium
-	}
-	unknownAssessmentStep = func(interface{}) (Result, string, ConfidenceLevel) {
+	})
+	unknownAssessmentStep = AssessmentStep(func(interface{}) (Result, string, ConfidenceLevel) {
 		return Unknown, "", Undetermined
-	}
+	})
 )
 
 func failingAssessmentPtr() *AssessmentLog {
@@ -32,7 +32,7 @@ func failingAssessment() AssessmentLog {
 			EntryId: "failingAssessment()",
 		},
 		Description: "failing assessment",
-		Steps: []AssessmentStep{
+		Steps: StepList{
 			failingAssessmentStep,
 			passingAssessmentStep,
 		},
@@ -50,7 +50,7 @@ func passingAssessment() AssessmentLog {
 			EntryId: "passingAssessment()",
 		},
 		Description: "passing assessment",
-		Steps: []AssessmentStep{
+		Steps: StepList{
 			passingAssessmentStep,
 		},
 		Applicability: testingApplicability,
@@ -67,7 +67,7 @@ func needsReviewAssessment() AssessmentLog {
 			EntryId: "needsReviewAssessment()",
 		},
 		Description: "needs review assessment",
-		Steps: []AssessmentStep{
+		Steps: StepList{
 			passingAssessmentStep,
 			needsReviewAssessmentStep,
 			passingAssessmentStep,
@@ -86,7 +86,7 @@ func unknownAssessment() AssessmentLog {
 			EntryId: "unknownAssessment()",
 		},
 		Description: "unknown assessment",
-		Steps: []AssessmentStep{
+		Steps: StepList{
 			passingAssessmentStep,
 			unknownAssessmentStep,
 			passingAssessmentStep,
@@ -101,7 +101,7 @@ func badRevertPassingAssessment() AssessmentLog {
 			EntryId: "badRevertPassingAssessment()",
 		},
 		Description: "bad revert passing assessment",
-		Steps: []AssessmentStep{
+		Steps: StepList{
 			passingAssessmentStep,
 			passingAssessmentStep,
 			passingAssessmentStep,
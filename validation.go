@@ -0,0 +1,521 @@
+package gemara
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity string
+
+const (
+	// SeverityError indicates the document is inconsistent and should be rejected.
+	SeverityError Severity = "error"
+	// SeverityWarning indicates a likely mistake that does not make the document unusable.
+	SeverityWarning Severity = "warning"
+)
+
+// DiagnosticCode identifies the kind of problem reported by a Diagnostic.
+type DiagnosticCode string
+
+const (
+	// CodeDuplicateId indicates that an id is used more than once where it must be unique.
+	CodeDuplicateId DiagnosticCode = "duplicate-id"
+	// CodeMissingField indicates that a field required for referential integrity is empty.
+	CodeMissingField DiagnosticCode = "missing-field"
+	// CodeUnknownFamily indicates that a control or guideline references a family that is not defined.
+	CodeUnknownFamily DiagnosticCode = "unknown-family"
+	// CodeUnknownMappingReference indicates that a reference-id has no matching MappingReference in the metadata.
+	CodeUnknownMappingReference DiagnosticCode = "unknown-mapping-reference"
+	// CodeStrengthOutOfRange indicates that a MappingEntry strength is outside of the 1-10 range.
+	CodeStrengthOutOfRange DiagnosticCode = "strength-out-of-range"
+	// CodeUnknownSeeAlso indicates that a Guideline.SeeAlso entry does not match a guideline in the document.
+	CodeUnknownSeeAlso DiagnosticCode = "unknown-see-also"
+	// CodeUnknownExtends indicates that a Guideline.Extends entry does not match a guideline in the document.
+	CodeUnknownExtends DiagnosticCode = "unknown-extends"
+	// CodeExtendsFamilyMismatch indicates that a guideline extends a local guideline from another family.
+	CodeExtendsFamilyMismatch DiagnosticCode = "extends-family-mismatch"
+	// CodeUnknownApplicability indicates that an applicability value is not a declared applicability category.
+	CodeUnknownApplicability DiagnosticCode = "unknown-applicability"
+	// CodeUnknownControl indicates that a control id does not exist in the provided catalogs.
+	CodeUnknownControl DiagnosticCode = "unknown-control"
+	// CodeUnknownRequirement indicates that an assessment requirement id does not exist in the provided catalogs.
+	CodeUnknownRequirement DiagnosticCode = "unknown-requirement"
+	// CodeUnknownTarget indicates that an exclusion, constraint or modification targets an id that does not exist in the provided catalogs.
+	CodeUnknownTarget DiagnosticCode = "unknown-target"
+	// CodeReferenceMismatch indicates that an assessment log references a different document than its control evaluation.
+	CodeReferenceMismatch DiagnosticCode = "reference-mismatch"
+	// CodeStepsExecuted indicates that more steps were executed than an assessment log defines.
+	CodeStepsExecuted DiagnosticCode = "steps-executed"
+	// CodeMissingSteps indicates that no steps are assigned to an assessment requirement or assessment plan.
+	CodeMissingSteps DiagnosticCode = "missing-steps"
+	// CodeUnregisteredStep indicates that a step assigned to an assessment requirement or plan is not registered.
+	CodeUnregisteredStep DiagnosticCode = "unregistered-step"
+	// CodeInvalidAssertion indicates that a procedure assertion has an invalid path, pattern or expected value.
+	CodeInvalidAssertion DiagnosticCode = "invalid-assertion"
+	// CodeMissingEvidence indicates that an assessment has a result but none of the evidence its assessment plan requires.
+	CodeMissingEvidence DiagnosticCode = "missing-evidence"
+	// CodeInvalidParameter indicates that a parameter value is missing, not accepted or not defined by its assessment plan.
+	CodeInvalidParameter DiagnosticCode = "invalid-parameter"
+	// CodeUnreadableDocument indicates that a document cannot be read, parsed or assigned a kind.
+	CodeUnreadableDocument DiagnosticCode = "unreadable-document"
+)
+
+// Diagnostic describes a single semantic problem found in a document.
+type Diagnostic struct {
+	Code     DiagnosticCode
+	Severity Severity
+	// Path locates the offending field using the serialized field names, e.g. "controls[2].family".
+	Path    string
+	Message string
+	// Position locates the offending field in the source document, when known. See WithPositions.
+	Position Position
+}
+
+func (d Diagnostic) String() string {
+	if d.Position.IsValid() {
+		return fmt.Sprintf("%s: %s: %s [%s] %s", d.Position, d.Severity, d.Path, d.Code, d.Message)
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", d.Severity, d.Path, d.Code, d.Message)
+}
+
+// Diagnostics is the list of problems found while validating a document.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any diagnostic has SeverityError.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns an error summarizing every diagnostic with SeverityError, or nil if there are none.
+func (d Diagnostics) Err() error {
+	var messages []string
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			messages = append(messages, diagnostic.String())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("validation failed with %d error(s):\n%s", len(messages), strings.Join(messages, "\n"))
+}
+
+type validateOpts struct {
+	catalogs  []*Catalog
+	policies  []*Policy
+	positions Positions
+}
+
+// ValidateOption defines an option to tune the behavior of the Validate methods.
+type ValidateOption func(opts *validateOpts)
+
+// WithCatalogs is a ValidateOption that provides the catalogs referenced by a Policy or EvaluationLog.
+// When set, control and assessment requirement ids are checked against these catalogs.
+func WithCatalogs(catalogs ...*Catalog) ValidateOption {
+	return func(opts *validateOpts) {
+		opts.catalogs = append(opts.catalogs, catalogs...)
+	}
+}
+
+// WithPolicies is a ValidateOption that provides the policies whose assessment plans an EvaluationLog executes.
+// When set, assessments of plans with evidence requirements are checked for evidence, and the
+// parameters recorded on assessments are checked against their plans.
+func WithPolicies(policies ...*Policy) ValidateOption {
+	return func(opts *validateOpts) {
+		opts.policies = append(opts.policies, policies...)
+	}
+}
+
+// WithPositions is a ValidateOption that provides the positions of the validated document in its
+// source, as returned by DecodeWithPositions. When set, every diagnostic carries the line and
+// column of the field it is about, or of its closest enclosing field.
+func WithPositions(positions Positions) ValidateOption {
+	return func(opts *validateOpts) {
+		opts.positions = positions
+	}
+}
+
+type validator struct {
+	opts        validateOpts
+	metadata    Metadata
+	diagnostics Diagnostics
+}
+
+func newValidator(metadata Metadata, opts []ValidateOption) *validator {
+	v := &validator{metadata: metadata}
+	for _, opt := range opts {
+		opt(&v.opts)
+	}
+	return v
+}
+
+func (v *validator) report(severity Severity, code DiagnosticCode, path string, format string, args ...interface{}) {
+	diagnostic := Diagnostic{
+		Code:     code,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if position, ok := v.opts.positions.Lookup(path); ok {
+		diagnostic.Position = position
+	}
+	v.diagnostics = append(v.diagnostics, diagnostic)
+}
+
+// unique reports a duplicate id error for every id seen more than once.
+func (v *validator) unique(seen map[string]string, id string, path string) {
+	if id == "" {
+		return
+	}
+	if first, ok := seen[id]; ok {
+		v.report(SeverityError, CodeDuplicateId, path, "id %q is already used at %s", id, first)
+		return
+	}
+	seen[id] = path
+}
+
+// reference checks that referenceId matches a MappingReference or the document itself.
+func (v *validator) reference(referenceId string, path string) {
+	if referenceId == "" || referenceId == v.metadata.Id {
+		return
+	}
+	if _, ok := findMappingReference(v.metadata, referenceId); !ok {
+		v.report(SeverityError, CodeUnknownMappingReference, path, "reference-id %q does not match any mapping reference in metadata", referenceId)
+	}
+}
+
+func (v *validator) multiMappings(mappings []MultiMapping, path string) {
+	for i, mapping := range mappings {
+		mappingPath := fmt.Sprintf("%s[%d]", path, i)
+		v.reference(mapping.ReferenceId, mappingPath+".reference-id")
+		for j, entry := range mapping.Entries {
+			if entry.Strength != 0 && (entry.Strength < 1 || entry.Strength > 10) {
+				v.report(SeverityError, CodeStrengthOutOfRange, fmt.Sprintf("%s.entries[%d].strength", mappingPath, j),
+					"strength %d is outside of the range 1-10", entry.Strength)
+			}
+		}
+	}
+}
+
+func (v *validator) applicability(values []string, path string) {
+	categories := v.metadata.ApplicabilityCategories
+	if len(categories) == 0 {
+		return
+	}
+	for i, value := range values {
+		var found bool
+		for _, category := range categories {
+			if value == category.Id || value == category.Title {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(SeverityWarning, CodeUnknownApplicability, fmt.Sprintf("%s[%d]", path, i),
+				"applicability %q does not match any applicability category in metadata", value)
+		}
+	}
+}
+
+// catalogControls indexes the controls and assessment requirements of the provided catalogs.
+func (v *validator) catalogControls() []Control {
+	var controls []Control
+	for _, catalog := range v.opts.catalogs {
+		if catalog != nil {
+			controls = append(controls, catalog.Controls...)
+		}
+	}
+	return controls
+}
+
+// catalogControl finds the control with the given id in the catalogs provided with WithCatalogs.
+func (v *validator) catalogControl(id string) (Control, bool) {
+	for _, catalog := range v.opts.catalogs {
+		if catalog == nil {
+			continue
+		}
+		if control, ok := findControl(catalog, id); ok {
+			return control, true
+		}
+	}
+	return Control{}, false
+}
+
+// Validate checks the catalog for problems that the schema cannot express, such as
+// references to undefined families or mapping references and duplicated ids.
+func (c *Catalog) Validate(opts ...ValidateOption) Diagnostics {
+	v := newValidator(c.Metadata, opts)
+
+	families := make(map[string]string)
+	for i, family := range c.Families {
+		v.unique(families, family.Id, fmt.Sprintf("families[%d].id", i))
+	}
+	controls := make(map[string]string)
+	requirements := make(map[string]string)
+	for i, control := range c.Controls {
+		path := fmt.Sprintf("controls[%d]", i)
+		v.unique(controls, control.Id, path+".id")
+		if _, ok := families[control.Family]; !ok {
+			v.report(SeverityError, CodeUnknownFamily, path+".family", "family %q is not defined in the catalog", control.Family)
+		}
+		for j, requirement := range control.AssessmentRequirements {
+			requirementPath := fmt.Sprintf("%s.assessment-requirements[%d]", path, j)
+			v.unique(requirements, requirement.Id, requirementPath+".id")
+			v.applicability(requirement.Applicability, requirementPath+".applicability")
+		}
+		v.multiMappings(control.GuidelineMappings, path+".guideline-mappings")
+		v.multiMappings(control.ThreatMappings, path+".threat-mappings")
+	}
+	threats := make(map[string]string)
+	for i, threat := range c.Threats {
+		path := fmt.Sprintf("threats[%d]", i)
+		v.unique(threats, threat.Id, path+".id")
+		v.multiMappings(threat.Capabilities, path+".capabilities")
+		v.multiMappings(threat.ExternalMappings, path+".external-mappings")
+	}
+	capabilities := make(map[string]string)
+	for i, capability := range c.Capabilities {
+		v.unique(capabilities, capability.Id, fmt.Sprintf("capabilities[%d].id", i))
+	}
+	v.multiMappings(c.ImportedControls, "imported-controls")
+	v.multiMappings(c.ImportedThreats, "imported-threats")
+	v.multiMappings(c.ImportedCapabilities, "imported-capabilities")
+
+	return v.diagnostics
+}
+
+// Validate checks the guidance document for problems that the schema cannot express, such as
+// references to undefined families, guidelines or mapping references and duplicated ids.
+func (g *GuidanceDocument) Validate(opts ...ValidateOption) Diagnostics {
+	v := newValidator(g.Metadata, opts)
+
+	families := make(map[string]string)
+	for i, family := range g.Families {
+		v.unique(families, family.Id, fmt.Sprintf("families[%d].id", i))
+	}
+	guidelines := make(map[string]string)
+	statements := make(map[string]string)
+	for i, guideline := range g.Guidelines {
+		path := fmt.Sprintf("guidelines[%d]", i)
+		v.unique(guidelines, guideline.Id, path+".id")
+		if _, ok := families[guideline.Family]; !ok {
+			v.report(SeverityError, CodeUnknownFamily, path+".family", "family %q is not defined in the guidance document", guideline.Family)
+		}
+		for j, statement := range guideline.Statements {
+			v.unique(statements, statement.Id, fmt.Sprintf("%s.statements[%d].id", path, j))
+		}
+		v.applicability(guideline.Applicability, path+".applicability")
+		v.multiMappings(guideline.GuidelineMappings, path+".guideline-mappings")
+		v.multiMappings(guideline.PrincipleMappings, path+".principle-mappings")
+	}
+
+	// References are checked once every guideline id is known, since they may point forward.
+	for i, guideline := range g.Guidelines {
+		path := fmt.Sprintf("guidelines[%d]", i)
+		for j, id := range guideline.SeeAlso {
+			if _, ok := guidelines[id]; !ok {
+				v.report(SeverityError, CodeUnknownSeeAlso, fmt.Sprintf("%s.see-also[%d]", path, j), "guideline %q is not defined in the guidance document", id)
+			}
+		}
+		if guideline.Extends == nil {
+			continue
+		}
+		if guideline.Extends.ReferenceId != "" && guideline.Extends.ReferenceId != g.Metadata.Id {
+			v.reference(guideline.Extends.ReferenceId, path+".extends.reference-id")
+			continue
+		}
+		extended, ok := findGuideline(g, guideline.Extends.EntryId)
+		if !ok {
+			v.report(SeverityError, CodeUnknownExtends, path+".extends.entry-id", "guideline %q is not defined in the guidance document", guideline.Extends.EntryId)
+			continue
+		}
+		if extended.Family != guideline.Family {
+			v.report(SeverityError, CodeExtendsFamilyMismatch, path+".family",
+				"guideline extends %q from family %q and must be in the same family", extended.Id, extended.Family)
+		}
+	}
+	for i, exemption := range g.Exemptions {
+		if exemption.Redirect != nil {
+			v.multiMappings([]MultiMapping{*exemption.Redirect}, fmt.Sprintf("exemptions[%d].redirect", i))
+		}
+	}
+
+	return v.diagnostics
+}
+
+// Validate checks the policy for problems that the schema cannot express, such as imports
+// without a mapping reference and duplicated assessment plan ids.
+// When catalogs are provided with WithCatalogs, exclusions, constraints, modifications and
+// assessment plans are checked against the controls and assessment requirements they define.
+func (p *Policy) Validate(opts ...ValidateOption) Diagnostics {
+	v := newValidator(p.Metadata, opts)
+	controls := v.catalogControls()
+	haveCatalogs := len(v.opts.catalogs) > 0
+
+	added := make(map[string]bool)
+	for i, catalogImport := range p.Imports.Catalogs {
+		path := fmt.Sprintf("imports.catalogs[%d]", i)
+		v.reference(catalogImport.ReferenceId, path+".reference-id")
+		for _, modifier := range catalogImport.AssessmentRequirementModifications {
+			if modifier.ModificationType == ModTypeAdd || modifier.ModificationType == ModTypeReplace {
+				added[modifier.Id] = true
+			}
+		}
+		if !haveCatalogs {
+			continue
+		}
+		for j, id := range catalogImport.Exclusions {
+			if !hasControlOrRequirement(controls, id) {
+				v.report(SeverityError, CodeUnknownTarget, fmt.Sprintf("%s.exclusions[%d]", path, j), "%q is not a control or assessment requirement", id)
+			}
+		}
+		for j, constraint := range catalogImport.Constraints {
+			if !hasControlOrRequirement(controls, constraint.TargetId) && !added[constraint.TargetId] {
+				v.report(SeverityError, CodeUnknownTarget, fmt.Sprintf("%s.constraints[%d].target-id", path, j), "%q is not a control or assessment requirement", constraint.TargetId)
+			}
+		}
+		for j, modifier := range catalogImport.AssessmentRequirementModifications {
+			if !hasControlOrRequirement(controls, modifier.TargetId) {
+				v.report(SeverityError, CodeUnknownTarget, fmt.Sprintf("%s.assessment-requirement-modifications[%d].target-id", path, j), "%q is not a control or assessment requirement", modifier.TargetId)
+			}
+		}
+	}
+	for i, guidanceImport := range p.Imports.Guidance {
+		v.reference(guidanceImport.ReferenceId, fmt.Sprintf("imports.guidance[%d].reference-id", i))
+	}
+	v.multiMappings(p.Risks.Mitigated, "risks.mitigated")
+	for i, accepted := range p.Risks.Accepted {
+		v.reference(accepted.Risk.ReferenceId, fmt.Sprintf("risks.accepted[%d].risk.reference-id", i))
+	}
+
+	plans := make(map[string]string)
+	for i, plan := range p.Adherence.AssessmentPlans {
+		path := fmt.Sprintf("adherence.assessment-plans[%d]", i)
+		v.unique(plans, plan.Id, path+".id")
+		parameters := make(map[string]string)
+		for j, parameter := range plan.Parameters {
+			v.unique(parameters, parameter.Id, fmt.Sprintf("%s.parameters[%d].id", path, j))
+			if parameter.Value != "" && !acceptsValue(parameter, parameter.Value) {
+				v.report(SeverityError, CodeInvalidParameter, fmt.Sprintf("%s.parameters[%d].value", path, j),
+					"value %q is not one of the accepted values: %s", parameter.Value, strings.Join(parameter.AcceptedValues, ", "))
+			}
+		}
+		if plan.RequirementId == "" {
+			v.report(SeverityError, CodeMissingField, path+".requirement-id", "assessment plan %q does not reference an assessment requirement", plan.Id)
+			continue
+		}
+		if haveCatalogs && !added[plan.RequirementId] && !hasRequirement(controls, plan.RequirementId) {
+			v.report(SeverityError, CodeUnknownRequirement, path+".requirement-id", "assessment requirement %q is not defined in the provided catalogs", plan.RequirementId)
+		}
+	}
+
+	return v.diagnostics
+}
+
+// Validate checks the evaluation log for problems that the schema cannot express, such as
+// mismatched references and inconsistent step counts.
+// When catalogs are provided with WithCatalogs, control and requirement ids are checked against them.
+func (e *EvaluationLog) Validate(opts ...ValidateOption) Diagnostics {
+	v := newValidator(e.Metadata, opts)
+	haveCatalogs := len(v.opts.catalogs) > 0
+
+	evaluated := make(map[string]string)
+	for i, evaluation := range e.Evaluations {
+		if evaluation == nil {
+			continue
+		}
+		path := fmt.Sprintf("evaluations[%d]", i)
+		if first, ok := evaluated[evaluation.Control.EntryId]; ok {
+			v.report(SeverityWarning, CodeDuplicateId, path+".control.entry-id", "control %q is already evaluated at %s", evaluation.Control.EntryId, first)
+		} else {
+			evaluated[evaluation.Control.EntryId] = path + ".control.entry-id"
+		}
+		if len(v.metadata.MappingReferences) > 0 {
+			v.reference(evaluation.Control.ReferenceId, path+".control.reference-id")
+		}
+		var control *Control
+		if haveCatalogs {
+			if found, ok := v.catalogControl(evaluation.Control.EntryId); ok {
+				control = &found
+			} else {
+				v.report(SeverityError, CodeUnknownControl, path+".control.entry-id", "control %q is not defined in the provided catalogs", evaluation.Control.EntryId)
+			}
+		}
+
+		for j, log := range evaluation.AssessmentLogs {
+			if log == nil {
+				continue
+			}
+			logPath := fmt.Sprintf("%s.assessment-logs[%d]", path, j)
+			if log.Requirement.ReferenceId != "" && log.Requirement.ReferenceId != evaluation.Control.ReferenceId {
+				v.report(SeverityError, CodeReferenceMismatch, logPath+".requirement.reference-id",
+					"reference-id %q does not match the control reference-id %q", log.Requirement.ReferenceId, evaluation.Control.ReferenceId)
+			}
+			if log.Plan != nil && len(v.metadata.MappingReferences) > 0 {
+				v.reference(log.Plan.ReferenceId, logPath+".plan.reference-id")
+			}
+			if log.StepsExecuted > int64(len(log.Steps)) {
+				v.report(SeverityError, CodeStepsExecuted, logPath+".steps-executed",
+					"%d steps were executed but only %d are defined", log.StepsExecuted, len(log.Steps))
+			}
+			if control != nil && !hasRequirement([]Control{*control}, log.Requirement.EntryId) {
+				v.report(SeverityError, CodeUnknownRequirement, logPath+".requirement.entry-id",
+					"assessment requirement %q is not defined for control %q", log.Requirement.EntryId, control.Id)
+			}
+			if plan, ok := v.assessmentPlan(log.Plan); ok {
+				v.evidence(log, plan, logPath)
+				if log.Parameters != nil {
+					if _, err := BindParameters(plan, log.Parameters); err != nil {
+						v.report(SeverityError, CodeInvalidParameter, logPath+".parameters", "%v", err)
+					}
+				}
+			}
+		}
+	}
+
+	return v.diagnostics
+}
+
+// assessmentPlan finds the assessment plan mapped by plan in the policies provided with WithPolicies.
+func (v *validator) assessmentPlan(plan *SingleMapping) (AssessmentPlan, bool) {
+	if plan == nil {
+		return AssessmentPlan{}, false
+	}
+	for _, policy := range v.opts.policies {
+		if policy == nil || (plan.ReferenceId != "" && plan.ReferenceId != policy.Metadata.Id) {
+			continue
+		}
+		for _, candidate := range policy.Adherence.AssessmentPlans {
+			if candidate.Id == plan.EntryId {
+				return candidate, true
+			}
+		}
+	}
+	return AssessmentPlan{}, false
+}
+
+// evidence checks that an assessment with a result carries evidence when its plan requires it.
+func (v *validator) evidence(log *AssessmentLog, plan AssessmentPlan, path string) {
+	if plan.EvidenceRequirements == "" || len(log.Evidence) > 0 || log.Result == NotRun || log.Result == NotApplicable {
+		return
+	}
+	v.report(SeverityError, CodeMissingEvidence, path+".evidence",
+		"assessment plan %q requires evidence (%s) but the %s result has none", plan.Id, plan.EvidenceRequirements, log.Result)
+}
+
+func hasRequirement(controls []Control, id string) bool {
+	for _, control := range controls {
+		for _, requirement := range control.AssessmentRequirements {
+			if requirement.Id == id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package gemara
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codes returns the code and path of every diagnostic for easy comparison.
+func codes(diagnostics Diagnostics) map[string]DiagnosticCode {
+	found := make(map[string]DiagnosticCode)
+	for _, d := range diagnostics {
+		found[d.Path] = d.Code
+	}
+	return found
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog Catalog
+		want    map[string]DiagnosticCode
+	}{
+		{
+			name: "valid catalog",
+			catalog: Catalog{
+				Metadata: Metadata{
+					Id:                "CAT",
+					MappingReferences: []MappingReference{{Id: "EXT"}},
+				},
+				Families: []Family{{Id: "FAM"}},
+				Controls: []Control{{
+					Id:                     "CTL-1",
+					Family:                 "FAM",
+					AssessmentRequirements: []AssessmentRequirement{{Id: "CTL-1.1"}},
+					GuidelineMappings:      []MultiMapping{{ReferenceId: "EXT", Entries: []MappingEntry{{ReferenceId: "G-1", Strength: 10}}}},
+					ThreatMappings:         []MultiMapping{{ReferenceId: "CAT", Entries: []MappingEntry{{ReferenceId: "THR-1"}}}},
+				}},
+				Threats: []Threat{{Id: "THR-1"}},
+			},
+			want: map[string]DiagnosticCode{},
+		},
+		{
+			name: "broken references and duplicates",
+			catalog: Catalog{
+				Metadata: Metadata{
+					Id:                      "CAT",
+					ApplicabilityCategories: []Category{{Id: "L1", Title: "Level 1"}},
+				},
+				Families: []Family{{Id: "FAM"}, {Id: "FAM"}},
+				Controls: []Control{
+					{
+						Id:     "CTL-1",
+						Family: "FAM",
+						AssessmentRequirements: []AssessmentRequirement{
+							{Id: "CTL-1.1", Applicability: []string{"Level 1", "L1"}},
+							{Id: "CTL-1.2", Applicability: []string{"Level 9"}},
+						},
+						GuidelineMappings: []MultiMapping{{ReferenceId: "MISSING", Entries: []MappingEntry{{ReferenceId: "G-1", Strength: 11}}}},
+					},
+					{Id: "CTL-1", Family: "NOPE", AssessmentRequirements: []AssessmentRequirement{{Id: "CTL-1.1"}}},
+				},
+				ImportedThreats: []MultiMapping{{ReferenceId: "UPSTREAM"}},
+			},
+			want: map[string]DiagnosticCode{
+				"families[1].id": CodeDuplicateId,
+				"controls[0].assessment-requirements[1].applicability[0]": CodeUnknownApplicability,
+				"controls[0].guideline-mappings[0].reference-id":          CodeUnknownMappingReference,
+				"controls[0].guideline-mappings[0].entries[0].strength":   CodeStrengthOutOfRange,
+				"controls[1].id":                            CodeDuplicateId,
+				"controls[1].family":                        CodeUnknownFamily,
+				"controls[1].assessment-requirements[0].id": CodeDuplicateId,
+				"imported-threats[0].reference-id":          CodeUnknownMappingReference,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := tt.catalog.Validate()
+			assert.Equal(t, tt.want, codes(diagnostics))
+		})
+	}
+}
+
+func TestCatalog_Validate_Files(t *testing.T) {
+	osps := &Catalog{}
+	require.NoError(t, osps.LoadFile("file://test-data/good-osps.yml"))
+	// The OSPS fixture maps guidelines to a reference that is missing from its metadata.
+	diagnostics := osps.Validate()
+	require.NotEmpty(t, diagnostics)
+	for _, d := range diagnostics {
+		assert.Equal(t, CodeUnknownMappingReference, d.Code, d.String())
+	}
+
+	imported := &Catalog{}
+	require.NoError(t, imported.LoadFile("file://test-data/good-imported-catalog.yaml"))
+	assert.Empty(t, imported.Validate())
+}
+
+func TestGuidanceDocument_Validate(t *testing.T) {
+	doc := GuidanceDocument{
+		Metadata: Metadata{
+			Id:                "GUIDE",
+			MappingReferences: []MappingReference{{Id: "EXT"}},
+		},
+		Families: []Family{{Id: "A"}, {Id: "B"}},
+		Guidelines: []Guideline{
+			{Id: "G-1", Family: "A", SeeAlso: []string{"G-2", "G-9"}},
+			{Id: "G-2", Family: "B", Extends: &SingleMapping{EntryId: "G-1"}},
+			{Id: "G-3", Family: "A", Extends: &SingleMapping{EntryId: "G-8"}},
+			{Id: "G-4", Family: "A", Extends: &SingleMapping{ReferenceId: "EXT", EntryId: "X-1"}},
+			{Id: "G-5", Family: "C", Extends: &SingleMapping{ReferenceId: "OTHER", EntryId: "X-1"}},
+			{Id: "G-1", Family: "A", PrincipleMappings: []MultiMapping{{ReferenceId: "EXT", Entries: []MappingEntry{{ReferenceId: "P", Strength: -1}}}}},
+		},
+	}
+
+	diagnostics := doc.Validate()
+	assert.True(t, diagnostics.HasErrors())
+	assert.Equal(t, map[string]DiagnosticCode{
+		"guidelines[0].see-also[1]":                               CodeUnknownSeeAlso,
+		"guidelines[1].family":                                    CodeExtendsFamilyMismatch,
+		"guidelines[2].extends.entry-id":                          CodeUnknownExtends,
+		"guidelines[4].family":                                    CodeUnknownFamily,
+		"guidelines[4].extends.reference-id":                      CodeUnknownMappingReference,
+		"guidelines[5].id":                                        CodeDuplicateId,
+		"guidelines[5].principle-mappings[0].entries[0].strength": CodeStrengthOutOfRange,
+	}, codes(diagnostics))
+
+	for _, file := range []string{"good-aigf.yaml", "good-extended-guidance.yaml"} {
+		g := &GuidanceDocument{}
+		require.NoError(t, g.LoadFile("file://test-data/"+file))
+		assert.Empty(t, g.Validate(), file)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	osps := &Catalog{}
+	require.NoError(t, osps.LoadFile("file://test-data/good-osps.yml"))
+	p := &Policy{}
+	require.NoError(t, p.LoadFile("file://test-data/good-osps-policy.yaml"))
+
+	assert.Empty(t, p.Validate(), "without catalogs only the policy itself is checked")
+	assert.Empty(t, p.Validate(WithCatalogs(osps)))
+
+	imp := &p.Imports.Catalogs[0]
+	imp.Exclusions = append(imp.Exclusions, "OSPS-ZZ-01")
+	imp.Constraints[0].TargetId = "OSPS-ZZ-02"
+	p.Adherence.AssessmentPlans[0].RequirementId = "OSPS-ZZ-01.01"
+	p.Adherence.AssessmentPlans[1].Id = p.Adherence.AssessmentPlans[0].Id
+	p.Imports.Guidance = append(p.Imports.Guidance, GuidanceImport{ReferenceId: "MISSING"})
+
+	diagnostics := p.Validate(WithCatalogs(osps))
+	assert.Equal(t, map[string]DiagnosticCode{
+		"imports.catalogs[0].exclusions[2]":            CodeUnknownTarget,
+		"imports.catalogs[0].constraints[0].target-id": CodeUnknownTarget,
+		"imports.guidance[0].reference-id":             CodeUnknownMappingReference,
+		"adherence.assessment-plans[0].requirement-id": CodeUnknownRequirement,
+		"adherence.assessment-plans[1].id":             CodeDuplicateId,
+	}, codes(diagnostics))
+	require.Error(t, diagnostics.Err())
+	assert.Contains(t, diagnostics.Err().Error(), "5 error(s)")
+}
+
+func TestEvaluationLog_Validate(t *testing.T) {
+	osps := &Catalog{}
+	require.NoError(t, osps.LoadFile("file://test-data/good-osps.yml"))
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+
+	assert.Empty(t, log.Validate(WithCatalogs(osps)))
+
+	log.Evaluations[0].AssessmentLogs[0].StepsExecuted = 3
+	log.Evaluations[1].AssessmentLogs[0].Requirement.ReferenceId = "OTHER"
+	log.Evaluations[1].AssessmentLogs[1].Requirement.EntryId = "OSPS-AC-01.01"
+	log.Evaluations[2].Control.EntryId = "OSPS-AC-01"
+
+	diagnostics := log.Validate(WithCatalogs(osps))
+	assert.Equal(t, map[string]DiagnosticCode{
+		"evaluations[0].assessment-logs[0].steps-executed":           CodeStepsExecuted,
+		"evaluations[1].assessment-logs[0].requirement.reference-id": CodeReferenceMismatch,
+		"evaluations[1].assessment-logs[1].requirement.entry-id":     CodeUnknownRequirement,
+		"evaluations[2].control.entry-id":                            CodeDuplicateId,
+		"evaluations[2].assessment-logs[0].requirement.entry-id":     CodeUnknownRequirement,
+	}, codes(diagnostics))
+
+	for _, d := range diagnostics {
+		if d.Code == CodeDuplicateId {
+			assert.Equal(t, SeverityWarning, d.Severity)
+		}
+	}
+}
+
+func TestEvaluationLog_Validate_Evidence(t *testing.T) {
+	policy := &Policy{}
+	require.NoError(t, policy.LoadFile("file://test-data/good-osps-policy.yaml"))
+	log := &EvaluationLog{}
+	require.NoError(t, log.LoadFile("file://test-data/good-evaluation-log.yaml"))
+
+	assert.Empty(t, log.Validate(WithPolicies(policy)))
+
+	log.Evaluations[0].AssessmentLogs[0].Evidence = nil
+	diagnostics := log.Validate(WithPolicies(policy))
+	assert.Equal(t, map[string]DiagnosticCode{
+		"evaluations[0].assessment-logs[0].evidence": CodeMissingEvidence,
+	}, codes(diagnostics))
+	assert.Equal(t, `assessment plan "EX-PLAN-AC-01" requires evidence (Organization security settings export) but the Passed result has none`, diagnostics[0].Message)
+
+	log.Evaluations[0].AssessmentLogs[0].Result = NotApplicable
+	assert.Empty(t, log.Validate(WithPolicies(policy)), "assessments without a result need no evidence")
+}